@@ -0,0 +1,37 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// AllFuture is the pending result of an All call started by AllAsync.
+type AllFuture struct {
+	done    chan struct{}
+	results map[string]Result
+}
+
+// AllAsync starts All in a separate goroutine and returns immediately. The
+// results are collected with the Wait method of the returned future.
+func AllAsync(ctx context.Context, retryInterval time.Duration, workers map[string]Worker, maxGs int, opts ...Option) *AllFuture {
+	f := AllFuture{done: make(chan struct{})}
+
+	go func() {
+		f.results = All(ctx, retryInterval, workers, maxGs, opts...)
+		close(f.done)
+	}()
+
+	return &f
+}
+
+// Done returns a channel that is closed when All completes.
+func (f *AllFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait blocks until All completes and returns its results. It can be called
+// any number of times, always returning the same results.
+func (f *AllFuture) Wait() map[string]Result {
+	<-f.done
+	return f.results
+}
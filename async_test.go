@@ -0,0 +1,38 @@
+package retry_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllAsync(t *testing.T) {
+	t.Run("wait", func(t *testing.T) {
+		t.Log("AllAsync should return immediately and Wait should return the cached results.")
+		release := make(chan struct{})
+		worker := func(ctx context.Context) (interface{}, error) {
+			<-release
+			return "ok", nil
+		}
+		workers := map[string]retry.Worker{"worker1": worker, "worker2": worker}
+		future := retry.AllAsync(context.Background(), time.Millisecond, workers, retry.MaxGoroutines)
+
+		select {
+		case <-future.Done():
+			t.Fatal("AllAsync should not be done before the worker functions return")
+		default:
+		}
+		close(release)
+
+		results := future.Wait()
+		assert.Len(t, results, 2)
+		for _, result := range results {
+			assert.NoError(t, result.Err)
+		}
+		assert.Equal(t, results, future.Wait())
+		<-future.Done()
+	})
+}
@@ -0,0 +1,114 @@
+package retry
+
+import "time"
+
+// Attempt describes a failed call to a worker function.
+type Attempt struct {
+	// Number is the number of failed attempts since the worker function
+	// started or last returned ErrProgress, starting at 1.
+	Number int
+
+	// Latency is how long the worker function call took.
+	Latency time.Duration
+
+	// Err is the error returned by the worker function.
+	Err error
+
+	// Slept is how long the worker function waited between its previous
+	// attempts.
+	Slept time.Duration
+}
+
+// Stop is returned by a Backoff to stop retrying the worker function. The
+// result then has an error with the ReasonMaxAttempts reason.
+const Stop time.Duration = -1
+
+// Backoff decides how long to wait before retrying a worker function. The
+// attempt carries all the state of the worker function, so the same Backoff
+// can be shared by the worker functions of All and First.
+type Backoff interface {
+	// Next returns how long to wait after the failed attempt.
+	Next(a Attempt) time.Duration
+}
+
+// UseBackoff sets the backoff that decides the wait between attempts,
+// instead of the fixed retry interval.
+func UseBackoff(b Backoff) Option {
+	return func(o *options) {
+		o.backoff = b
+	}
+}
+
+// delay returns how long to wait after the failed attempt.
+func (o *options) delay(retryInterval time.Duration, a Attempt) time.Duration {
+	if o.backoff == nil {
+		return retryInterval
+	}
+	return o.backoff.Next(a)
+}
+
+// cappedTotal implements CappedTotal.
+type cappedTotal struct {
+	inner    Backoff
+	totalMax time.Duration
+}
+
+// CappedTotal limits the total time the inner backoff makes a worker
+// function wait between its attempts to totalMax, apart from the context
+// deadline. The last wait is shortened to fit, and once the budget is
+// exhausted the backoff returns Stop.
+func CappedTotal(inner Backoff, totalMax time.Duration) Backoff {
+	return cappedTotal{inner: inner, totalMax: totalMax}
+}
+
+// Next implements the Backoff interface.
+func (b cappedTotal) Next(a Attempt) time.Duration {
+	left := b.totalMax - a.Slept
+	if left <= 0 {
+		return Stop
+	}
+	d := b.inner.Next(a)
+	if d == Stop || d <= left {
+		return d
+	}
+	return left
+}
+
+// AdaptiveBackoff waits a multiple of how long the failed attempt took, so
+// worker functions that fail fast are retried more often than the ones that
+// fail slowly.
+type AdaptiveBackoff struct {
+	// Base is the wait used when the attempt latency is unknown.
+	Base time.Duration
+
+	// Factor multiplies the attempt latency. Zero means 1.
+	Factor float64
+
+	// Min and Max clamp the wait. A zero Max means no upper limit.
+	Min time.Duration
+	Max time.Duration
+}
+
+// Next implements the Backoff interface.
+func (b AdaptiveBackoff) Next(a Attempt) time.Duration {
+	if a.Latency <= 0 {
+		return b.clamp(b.Base)
+	}
+
+	factor := b.Factor
+	if factor == 0 {
+		factor = 1
+	}
+	return b.clamp(time.Duration(factor * float64(a.Latency)))
+}
+
+// clamp limits the wait to the Min and Max configured.
+func (b AdaptiveBackoff) clamp(d time.Duration) time.Duration {
+	if d < b.Min {
+		return b.Min
+	}
+	if b.Max > 0 && d > b.Max {
+		return b.Max
+	}
+	return d
+}
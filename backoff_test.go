@@ -0,0 +1,123 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptiveBackoff(t *testing.T) {
+	t.Run("next", func(t *testing.T) {
+		t.Log("AdaptiveBackoff should wait a multiple of the attempt latency, clamped to min and max.")
+		b := retry.AdaptiveBackoff{Base: 10 * time.Millisecond, Factor: 2, Min: time.Millisecond, Max: 50 * time.Millisecond}
+		tt := []struct {
+			latency time.Duration
+			want    time.Duration
+		}{
+			{0, 10 * time.Millisecond},
+			{100 * time.Microsecond, time.Millisecond},
+			{5 * time.Millisecond, 10 * time.Millisecond},
+			{20 * time.Millisecond, 40 * time.Millisecond},
+			{time.Second, 50 * time.Millisecond},
+		}
+		for _, tc := range tt {
+			assert.Equal(t, tc.want, b.Next(retry.Attempt{Number: 1, Latency: tc.latency}), "latency %v", tc.latency)
+		}
+	})
+
+	t.Run("func", func(t *testing.T) {
+		t.Log("Func should use the backoff to wait between attempts.")
+		var attempts []retry.Attempt
+		b := backoffFunc(func(a retry.Attempt) time.Duration {
+			attempts = append(attempts, a)
+			return time.Nanosecond
+		})
+		errWork := errors.New("not yet")
+		worker := func(ctx context.Context) (interface{}, error) {
+			if len(attempts) < 2 {
+				return nil, errWork
+			}
+			return nil, nil
+		}
+		result := retry.Func(context.Background(), time.Hour, worker, retry.UseBackoff(b))
+		assert.NoError(t, result.Err)
+		if assert.Len(t, attempts, 2) {
+			assert.Equal(t, 1, attempts[0].Number)
+			assert.Equal(t, 2, attempts[1].Number)
+			assert.Equal(t, errWork, attempts[1].Err)
+		}
+	})
+}
+
+// backoffFunc adapts a function to the Backoff interface.
+type backoffFunc func(a retry.Attempt) time.Duration
+
+func (f backoffFunc) Next(a retry.Attempt) time.Duration {
+	return f(a)
+}
+
+func TestCappedTotal(t *testing.T) {
+	t.Run("cap", func(t *testing.T) {
+		t.Log("Func should stop retrying once the total wait reaches the cap.")
+		var attempts []retry.Attempt
+		inner := backoffFunc(func(a retry.Attempt) time.Duration {
+			return 4 * time.Millisecond
+		})
+		b := retry.CappedTotal(inner, 10*time.Millisecond)
+		errWork := errors.New("not yet")
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			return nil, errWork
+		}
+		observe := backoffFunc(func(a retry.Attempt) time.Duration {
+			attempts = append(attempts, a)
+			return b.Next(a)
+		})
+		result := retry.Func(context.Background(), time.Millisecond, worker, retry.UseBackoff(observe))
+		var err *retry.Error
+		if assert.True(t, errors.As(result.Err, &err)) {
+			assert.Equal(t, retry.ReasonMaxAttempts, err.Reason())
+			assert.True(t, errors.Is(err, errWork))
+		}
+		assert.Equal(t, 4, calls)
+		var slept []time.Duration
+		for _, a := range attempts {
+			slept = append(slept, a.Slept)
+		}
+		assert.Equal(t, []time.Duration{0, 4 * time.Millisecond, 8 * time.Millisecond, 10 * time.Millisecond}, slept)
+	})
+
+	t.Run("retryafter", func(t *testing.T) {
+		t.Log("Func should stop retrying once the RetryAfter delays reach the cap.")
+		inner := backoffFunc(func(a retry.Attempt) time.Duration {
+			return time.Millisecond
+		})
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			return nil, retry.RetryAfter(4*time.Millisecond, errors.New("busy"))
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		result := retry.Func(ctx, time.Millisecond, worker, retry.UseBackoff(retry.CappedTotal(inner, 10*time.Millisecond)))
+		var err *retry.Error
+		if assert.True(t, errors.As(result.Err, &err)) {
+			assert.Equal(t, retry.ReasonMaxAttempts, err.Reason())
+		}
+		assert.Equal(t, 4, calls)
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		t.Log("CappedTotal should pass Stop from the inner backoff through.")
+		inner := backoffFunc(func(a retry.Attempt) time.Duration {
+			return retry.Stop
+		})
+		b := retry.CappedTotal(inner, time.Second)
+		assert.Equal(t, retry.Stop, b.Next(retry.Attempt{Number: 1}))
+	})
+}
@@ -0,0 +1,120 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BreakerOptions configures the circuit breaker created by CircuitBreaker.
+type BreakerOptions struct {
+	// Failures is the number of consecutive failures that trips the breaker
+	// open. Values lower than 1 are treated as 1.
+	Failures int
+
+	// Cooldown is how long the breaker stays open before a trial call is
+	// let through to the worker function.
+	Cooldown time.Duration
+
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+}
+
+// BreakerOpenError is returned by a worker function wrapped by
+// CircuitBreaker while the breaker is open.
+type BreakerOpenError struct {
+	errWork error
+	until   time.Time
+}
+
+// Error implements the error interface and returns information about
+// the open breaker.
+func (err *BreakerOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker open until %v : %s", err.until, err.errWork)
+}
+
+// Unwrap returns the worker function error that tripped the breaker.
+func (err *BreakerOpenError) Unwrap() error {
+	return err.errWork
+}
+
+// Retryable reports that the call should not be retried, since the breaker
+// stays open until the cooldown passes.
+func (err *BreakerOpenError) Retryable() bool {
+	return false
+}
+
+// Breaker states.
+const (
+	breakerClosed = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// breaker holds the state of a worker function wrapped by CircuitBreaker.
+type breaker struct {
+	worker Worker
+	opts   BreakerOptions
+
+	mu       sync.Mutex
+	state    int
+	failures int
+	openedAt time.Time
+	errWork  error
+}
+
+// CircuitBreaker wraps the worker function with a circuit breaker. After
+// opts.Failures consecutive failures the breaker opens and the worker
+// function is not called, returning a *BreakerOpenError instead, until
+// opts.Cooldown passes. Then a single trial call is let through: if it
+// succeeds the breaker closes, otherwise it opens again. The returned worker
+// function is safe for concurrent use.
+func CircuitBreaker(worker Worker, opts BreakerOptions) Worker {
+	if opts.Failures < 1 {
+		opts.Failures = 1
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	b := breaker{worker: worker, opts: opts}
+	return b.work
+}
+
+// work calls the worker function unless the breaker is open.
+func (b *breaker) work(ctx context.Context) (interface{}, error) {
+	b.mu.Lock()
+	switch b.state {
+	case breakerOpen:
+		until := b.openedAt.Add(b.opts.Cooldown)
+		if b.opts.Now().Before(until) {
+			b.mu.Unlock()
+			return nil, &BreakerOpenError{errWork: b.errWork, until: until}
+		}
+		b.state = breakerHalfOpen
+	case breakerHalfOpen:
+		b.mu.Unlock()
+		return nil, &BreakerOpenError{errWork: b.errWork, until: b.openedAt.Add(b.opts.Cooldown)}
+	}
+	b.mu.Unlock()
+
+	value, err := b.worker(ctx)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err != nil {
+		b.failures++
+		if b.state == breakerHalfOpen || b.failures >= b.opts.Failures {
+			b.state = breakerOpen
+			b.openedAt = b.opts.Now()
+			b.errWork = err
+		}
+		return nil, err
+	}
+
+	b.state = breakerClosed
+	b.failures = 0
+	b.errWork = nil
+	return value, nil
+}
@@ -0,0 +1,110 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Run("transitions", func(t *testing.T) {
+		t.Log("CircuitBreaker should open after consecutive failures, let a trial through after the cooldown and close on success.")
+		now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		clock := func() time.Time { return now }
+		errWork := errors.New("down")
+		var calls int
+		fail := true
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			if fail {
+				return nil, errWork
+			}
+			return "up", nil
+		}
+		breaker := retry.CircuitBreaker(worker, retry.BreakerOptions{Failures: 2, Cooldown: time.Second, Now: clock})
+		ctx := context.Background()
+
+		t.Log("closed: failures reach the worker function")
+		for i := 0; i < 2; i++ {
+			_, err := breaker(ctx)
+			assert.Equal(t, errWork, err)
+		}
+		assert.Equal(t, 2, calls)
+
+		t.Log("open: calls are short-circuited")
+		_, err := breaker(ctx)
+		var errOpen *retry.BreakerOpenError
+		if assert.True(t, errors.As(err, &errOpen)) {
+			assert.False(t, errOpen.Retryable())
+			assert.Equal(t, errWork, errors.Unwrap(err))
+		}
+		assert.Equal(t, 2, calls)
+
+		t.Log("half-open: a failing trial opens the breaker again")
+		now = now.Add(time.Second)
+		_, err = breaker(ctx)
+		assert.Equal(t, errWork, err)
+		assert.Equal(t, 3, calls)
+		_, err = breaker(ctx)
+		assert.True(t, errors.As(err, &errOpen))
+		assert.Equal(t, 3, calls)
+
+		t.Log("half-open: a successful trial closes the breaker")
+		now = now.Add(time.Second)
+		fail = false
+		value, err := breaker(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "up", value)
+		assert.Equal(t, 4, calls)
+
+		t.Log("closed: a single failure does not trip the breaker")
+		fail = true
+		_, err = breaker(ctx)
+		assert.Equal(t, errWork, err)
+		fail = false
+		_, err = breaker(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 6, calls)
+	})
+
+	t.Run("func", func(t *testing.T) {
+		t.Log("CircuitBreaker should compose with Func.")
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			if calls < 3 {
+				return nil, errors.New("down")
+			}
+			return "up", nil
+		}
+		breaker := retry.CircuitBreaker(worker, retry.BreakerOptions{Failures: 5, Cooldown: time.Second})
+		result := retry.Func(context.Background(), time.Nanosecond, breaker)
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, "up", result.Value)
+		}
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		t.Log("Func should stop retrying once the breaker is open.")
+		errWork := errors.New("down")
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			return nil, errWork
+		}
+		breaker := retry.CircuitBreaker(worker, retry.BreakerOptions{Failures: 2, Cooldown: time.Hour})
+		result := retry.Func(context.Background(), time.Nanosecond, breaker)
+		if assert.Error(t, result.Err) {
+			assert.IsType(t, &retry.Error{}, result.Err)
+			var errOpen *retry.BreakerOpenError
+			assert.True(t, errors.As(result.Err, &errOpen))
+			assert.True(t, errors.Is(result.Err, errWork))
+			assert.Regexp(t, "retry stopped after .+ : circuit breaker open", result.Err.Error())
+		}
+		assert.Equal(t, 2, calls)
+	})
+}
@@ -0,0 +1,67 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// SplitBudget divides the time remaining until the context deadline into n
+// equal slices, for running n steps one after the other under the same
+// deadline. The i-th context ends after i+1 slices, so the time a step does
+// not use is left to the next ones, and the last context ends at the parent
+// deadline. A context without a deadline yields children without one, and an
+// expired context yields children that are already done. Call cancel to
+// release the children once the steps are done.
+func SplitBudget(ctx context.Context, n int) ([]context.Context, context.CancelFunc) {
+	if n <= 0 {
+		return nil, func() {}
+	}
+
+	ctxs := make([]context.Context, n)
+	cancels := make([]context.CancelFunc, n)
+	cancel := func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		for i := range ctxs {
+			ctxs[i], cancels[i] = context.WithCancel(ctx)
+		}
+		return ctxs, cancel
+	}
+
+	start := time.Now()
+	slice := deadline.Sub(start) / time.Duration(n)
+	for i := range ctxs {
+		end := start.Add(slice * time.Duration(i+1))
+		if i == n-1 {
+			end = deadline
+		}
+		ctxs[i], cancels[i] = context.WithDeadline(ctx, end)
+	}
+	return ctxs, cancel
+}
+
+// BudgetFromContext returns the budget left to the current worker function
+// call: the time remaining until the context deadline and how many more
+// attempts MaxAttempts allows after this one. remaining is -1 if the context
+// has no deadline, and attemptsLeft is -1 if the attempts are unlimited. ok
+// is false if the context was not provided by Func.
+func BudgetFromContext(ctx context.Context) (remaining time.Duration, attemptsLeft int, ok bool) {
+	a, ok := ctx.Value(attemptKey{}).(attemptState)
+	if !ok {
+		return 0, 0, false
+	}
+
+	remaining = -1
+	if deadline, has := ctx.Deadline(); has {
+		remaining = time.Until(deadline)
+		if remaining < 0 {
+			remaining = 0
+		}
+	}
+	return remaining, a.left, true
+}
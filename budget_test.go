@@ -0,0 +1,114 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitBudget(t *testing.T) {
+	t.Run("split", func(t *testing.T) {
+		t.Log("SplitBudget should divide the remaining time into equal slices.")
+		parent, cancelParent := context.WithTimeout(context.Background(), time.Second)
+		defer cancelParent()
+		start := time.Now()
+		deadline, _ := parent.Deadline()
+
+		ctxs, cancel := retry.SplitBudget(parent, 4)
+		defer cancel()
+		assert.Len(t, ctxs, 4)
+
+		var sum time.Duration
+		prev := start
+		for _, ctx := range ctxs {
+			end, ok := ctx.Deadline()
+			if assert.True(t, ok) {
+				assert.InDelta(t, int64(250*time.Millisecond), int64(end.Sub(prev)), float64(20*time.Millisecond))
+				sum += end.Sub(prev)
+				prev = end
+			}
+		}
+		assert.InDelta(t, int64(time.Second), int64(sum), float64(20*time.Millisecond))
+		assert.Equal(t, deadline, prev)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		t.Log("SplitBudget should return done contexts for an expired parent.")
+		parent, cancelParent := context.WithTimeout(context.Background(), -time.Second)
+		defer cancelParent()
+		ctxs, cancel := retry.SplitBudget(parent, 3)
+		defer cancel()
+		for _, ctx := range ctxs {
+			assert.Error(t, ctx.Err())
+		}
+	})
+
+	t.Run("nodeadline", func(t *testing.T) {
+		t.Log("SplitBudget should return contexts without a deadline for a parent without one.")
+		ctxs, cancel := retry.SplitBudget(context.Background(), 2)
+		for _, ctx := range ctxs {
+			_, ok := ctx.Deadline()
+			assert.False(t, ok)
+			assert.NoError(t, ctx.Err())
+		}
+		cancel()
+		for _, ctx := range ctxs {
+			assert.Error(t, ctx.Err())
+		}
+	})
+}
+
+func TestBudgetFromContext(t *testing.T) {
+	t.Run("budget", func(t *testing.T) {
+		t.Log("The worker function should read the time and attempts left.")
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		var lefts []int
+		worker := func(ctx context.Context) (interface{}, error) {
+			remaining, left, ok := retry.BudgetFromContext(ctx)
+			assert.True(t, ok)
+			assert.Greater(t, int64(remaining), int64(0))
+			assert.LessOrEqual(t, int64(remaining), int64(time.Second))
+			lefts = append(lefts, left)
+			return nil, errors.New("not yet")
+		}
+		result := retry.Func(ctx, time.Millisecond, worker, retry.MaxAttempts(3))
+		assert.Error(t, result.Err)
+		assert.Equal(t, []int{2, 1, 0}, lefts)
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		t.Log("The worker function should read -1 without deadline or attempts limit.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			remaining, left, ok := retry.BudgetFromContext(ctx)
+			assert.True(t, ok)
+			assert.Equal(t, time.Duration(-1), remaining)
+			assert.Equal(t, -1, left)
+			return "ok", nil
+		}
+		result := retry.Func(context.Background(), time.Millisecond, worker)
+		assert.NoError(t, result.Err)
+	})
+
+	t.Run("try", func(t *testing.T) {
+		t.Log("The worker function called by Try should read no attempts left.")
+		var left int
+		worker := func(ctx context.Context) (interface{}, error) {
+			_, left, _ = retry.BudgetFromContext(ctx)
+			return nil, errors.New("failed")
+		}
+		result := retry.Try(context.Background(), worker)
+		assert.Error(t, result.Err)
+		assert.Equal(t, 0, left)
+	})
+
+	t.Run("noattempt", func(t *testing.T) {
+		t.Log("BudgetFromContext should not be ok outside of Func.")
+		_, _, ok := retry.BudgetFromContext(context.Background())
+		assert.False(t, ok)
+	})
+}
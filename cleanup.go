@@ -0,0 +1,68 @@
+package retry
+
+import (
+	"context"
+	"sync"
+)
+
+// cleanupKey is the context key used to store the cleanups of a worker
+// function.
+type cleanupKey struct{}
+
+// cleanups holds the cleanup functions registered while retrying a worker
+// function, each one once.
+type cleanups struct {
+	mu   sync.Mutex
+	ids  map[*int]bool
+	fns  []func(Result)
+	done bool
+}
+
+// add registers the cleanup function with the id of its WithCleanup, unless
+// it is already registered. It returns false if the cleanups already ran.
+func (c *cleanups) add(id *int, cleanup func(Result)) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.done {
+		return false
+	}
+	if c.ids == nil {
+		c.ids = make(map[*int]bool)
+	}
+	if !c.ids[id] {
+		c.ids[id] = true
+		c.fns = append(c.fns, cleanup)
+	}
+	return true
+}
+
+// run calls the cleanup functions with the result, the last registered
+// first, like deferred calls.
+func (c *cleanups) run(result Result) {
+	c.mu.Lock()
+	c.done = true
+	fns := c.fns
+	c.mu.Unlock()
+
+	for i := len(fns) - 1; i >= 0; i-- {
+		fns[i](result)
+	}
+}
+
+// WithCleanup wraps the worker function so cleanup is called exactly once,
+// with the final result, when Func, All or First stops retrying it: after
+// it succeeds, times out or is cancelled. Called directly, outside of this
+// package, the worker function runs cleanup after each call.
+func WithCleanup(worker Worker, cleanup func(result Result)) Worker {
+	id := new(int)
+	return func(ctx context.Context) (interface{}, error) {
+		if c, ok := ctx.Value(cleanupKey{}).(*cleanups); ok && c.add(id, cleanup) {
+			return worker(ctx)
+		}
+
+		value, err := worker(ctx)
+		cleanup(Result{Value: value, Err: err})
+		return value, err
+	}
+}
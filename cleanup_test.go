@@ -0,0 +1,98 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithCleanup(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		t.Log("Func should run the cleanup once with the successful result.")
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			if calls < 3 {
+				return nil, errors.New("not yet")
+			}
+			return "done", nil
+		}
+		var cleaned []retry.Result
+		cleanup := func(result retry.Result) {
+			cleaned = append(cleaned, result)
+		}
+		result := retry.Func(context.Background(), time.Millisecond, retry.WithCleanup(worker, cleanup))
+		assert.NoError(t, result.Err)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, []retry.Result{result}, cleaned)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Log("Func should run the cleanup once with the timeout error.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return nil, errors.New("not yet")
+		}
+		var cleaned []retry.Result
+		cleanup := func(result retry.Result) {
+			cleaned = append(cleaned, result)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		result := retry.Func(ctx, time.Millisecond, retry.WithCleanup(worker, cleanup))
+		assert.Error(t, result.Err)
+		assert.Equal(t, []retry.Result{result}, cleaned)
+	})
+
+	t.Run("first", func(t *testing.T) {
+		t.Log("First should run the cleanup of each worker function once.")
+		started := make(chan struct{})
+		winner := func(ctx context.Context) (interface{}, error) {
+			<-started
+			return "winner", nil
+		}
+		loser := func(ctx context.Context) (interface{}, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		var mu sync.Mutex
+		cleaned := make(map[string]int)
+		var wg sync.WaitGroup
+		wg.Add(2)
+		cleanup := func(name string) func(retry.Result) {
+			return func(retry.Result) {
+				mu.Lock()
+				defer mu.Unlock()
+				cleaned[name]++
+				wg.Done()
+			}
+		}
+		workers := map[string]retry.Worker{
+			"winner": retry.WithCleanup(winner, cleanup("winner")),
+			"loser":  retry.WithCleanup(loser, cleanup("loser")),
+		}
+		result := retry.First(context.Background(), time.Millisecond, workers, retry.MaxGoroutines)
+		assert.NoError(t, result.Err)
+		wg.Wait()
+		assert.Equal(t, map[string]int{"winner": 1, "loser": 1}, cleaned)
+	})
+
+	t.Run("direct", func(t *testing.T) {
+		t.Log("The worker function should run the cleanup after a direct call.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return "direct", nil
+		}
+		var cleaned []retry.Result
+		cleanup := func(result retry.Result) {
+			cleaned = append(cleaned, result)
+		}
+		value, err := retry.WithCleanup(worker, cleanup)(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, []retry.Result{{Value: value}}, cleaned)
+	})
+}
@@ -0,0 +1,56 @@
+package retry
+
+import "sort"
+
+// CoalesceBy makes All run a single execution for all the worker functions
+// that share the same key, fanning the result out to each of their names.
+// The key is authoritative: worker functions with the same key are assumed
+// to perform the same work, even if they are different closures, and only
+// the one with the lowest name is called. It only affects All and AllAsync;
+// Func, First and FirstN ignore it.
+func CoalesceBy(key func(name string) string) Option {
+	return func(o *options) {
+		o.key = key
+	}
+}
+
+// coalesce keeps one worker function per key. It returns the worker
+// functions to run and, for each one of them, the names sharing its result.
+func (o *options) coalesce(workers map[string]Worker) (map[string]Worker, map[string][]string) {
+	if o.key == nil {
+		return workers, nil
+	}
+
+	names := make([]string, 0, len(workers))
+	for name := range workers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	shared := make(map[string]Worker)
+	aliases := make(map[string][]string)
+	owners := make(map[string]string)
+	for _, name := range names {
+		key := o.key(name)
+		owner, ok := owners[key]
+		if !ok {
+			owners[key] = name
+			shared[name] = workers[name]
+			continue
+		}
+		aliases[owner] = append(aliases[owner], name)
+	}
+
+	return shared, aliases
+}
+
+// alias returns the result shared with the named alias, with its own copy
+// of the *Error, if any, so the error reports the alias name.
+func alias(result Result, name string) Result {
+	if err, ok := result.Err.(*Error); ok {
+		copied := *err
+		copied.name = name
+		result.Err = &copied
+	}
+	return result
+}
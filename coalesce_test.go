@@ -0,0 +1,55 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoalesceBy(t *testing.T) {
+	t.Run("shared", func(t *testing.T) {
+		t.Log("All should run worker functions sharing a key only once.")
+		var calls int32
+		worker := func(ctx context.Context) (interface{}, error) {
+			return atomic.AddInt32(&calls, 1), nil
+		}
+		other := func(ctx context.Context) (interface{}, error) {
+			return "other", nil
+		}
+		workers := map[string]retry.Worker{"db/primary": worker, "db/report": worker, "cache": other}
+		key := func(name string) string {
+			return strings.Split(name, "/")[0]
+		}
+		results := retry.All(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, retry.CoalesceBy(key))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+		assert.Len(t, results, 3)
+		assert.Equal(t, int32(1), results["db/primary"].Value)
+		assert.Equal(t, int32(1), results["db/report"].Value)
+		assert.Equal(t, "other", results["cache"].Value)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Log("All should name the alias in the error shared with a coalesced worker function.")
+		errDB := errors.New("db down")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return nil, retry.Permanent(errDB)
+		}
+		workers := map[string]retry.Worker{"db/primary": worker, "db/report": worker}
+		key := func(name string) string {
+			return strings.Split(name, "/")[0]
+		}
+		results := retry.All(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, retry.CoalesceBy(key))
+		assert.Regexp(t, `^worker "db/primary" `, results["db/primary"].Err.Error())
+		assert.Regexp(t, `^worker "db/report" `, results["db/report"].Err.Error())
+		assert.True(t, errors.Is(results["db/report"].Err, errDB))
+
+		_, err := retry.AllErr(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, retry.CoalesceBy(key))
+		assert.Regexp(t, `worker "db/primary" .*; worker "db/report" `, err.Error())
+	})
+}
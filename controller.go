@@ -0,0 +1,72 @@
+package retry
+
+import (
+	"context"
+	"sync"
+)
+
+// Controller pauses and resumes the attempts of the worker functions using
+// it, see UseController. The zero value is ready to use, not paused, and a
+// Controller may be shared by many calls.
+type Controller struct {
+	mu      sync.Mutex
+	resumed chan struct{}
+}
+
+// UseController makes the worker functions wait for the controller to be
+// resumed before each attempt. The time spent paused counts against the
+// context, a deadline is not extended while paused.
+func UseController(c *Controller) Option {
+	return func(o *options) {
+		o.controller = c
+	}
+}
+
+// Pause stops new attempts from starting. Attempts in progress finish, and
+// the worker functions waiting to retry keep waiting until Resume.
+func (c *Controller) Pause() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.resumed == nil {
+		c.resumed = make(chan struct{})
+	}
+}
+
+// Resume lets the paused attempts start.
+func (c *Controller) Resume() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.resumed != nil {
+		close(c.resumed)
+		c.resumed = nil
+	}
+}
+
+// Paused reports whether the controller is paused.
+func (c *Controller) Paused() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.resumed != nil
+}
+
+// wait blocks while the controller is paused. It returns false if the
+// context ends first.
+func (c *Controller) wait(ctx context.Context) bool {
+	for {
+		c.mu.Lock()
+		resumed := c.resumed
+		c.mu.Unlock()
+
+		if resumed == nil {
+			return true
+		}
+
+		select {
+		case <-resumed:
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
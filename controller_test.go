@@ -0,0 +1,71 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUseController(t *testing.T) {
+	t.Run("pause", func(t *testing.T) {
+		t.Log("All should not start attempts while paused and complete once resumed.")
+		var calls, ready int32
+		worker := func(ctx context.Context) (interface{}, error) {
+			atomic.AddInt32(&calls, 1)
+			if atomic.LoadInt32(&ready) == 0 {
+				return nil, errors.New("not yet")
+			}
+			return "ok", nil
+		}
+		workers := make(map[string]retry.Worker)
+		for i := 0; i < 4; i++ {
+			workers[fmt.Sprint("worker", i)] = worker
+		}
+
+		var c retry.Controller
+		results := make(chan map[string]retry.Result)
+		go func() {
+			results <- retry.All(context.Background(), time.Millisecond, workers, 2, retry.UseController(&c))
+		}()
+
+		for atomic.LoadInt32(&calls) < 4 {
+			time.Sleep(time.Millisecond)
+		}
+		c.Pause()
+		assert.True(t, c.Paused())
+		time.Sleep(10 * time.Millisecond)
+		paused := atomic.LoadInt32(&calls)
+		time.Sleep(20 * time.Millisecond)
+		assert.Equal(t, paused, atomic.LoadInt32(&calls))
+
+		atomic.StoreInt32(&ready, 1)
+		c.Resume()
+		assert.False(t, c.Paused())
+		assert.Equal(t, 4, retry.CountSuccesses(<-results))
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Log("Func should time out while paused.")
+		var c retry.Controller
+		c.Pause()
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			return "ok", nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		result := retry.Func(ctx, time.Millisecond, worker, retry.UseController(&c))
+		var err *retry.Error
+		if assert.True(t, errors.As(result.Err, &err)) {
+			assert.Equal(t, retry.ReasonTimeout, err.Reason())
+		}
+		assert.Equal(t, 0, calls)
+	})
+}
@@ -0,0 +1,213 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrProgress is returned by a worker function that made progress but has
+// not finished yet. It is not counted as a failure: the worker function is
+// called again after the retry interval, and both the backoff and the
+// MaxAttempts count start over. The context still bounds how long the
+// worker function keeps making progress.
+var ErrProgress = errors.New("worker function made progress")
+
+// RetryableError marks a worker function error as retryable, overriding the
+// classification of the errors it wraps.
+type RetryableError struct {
+	err error
+}
+
+// Error implements the error interface and returns the wrapped error
+// message.
+func (err *RetryableError) Error() string {
+	return err.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (err *RetryableError) Unwrap() error {
+	return err.err
+}
+
+// Retryable reports that the error can be retried.
+func (err *RetryableError) Retryable() bool {
+	return true
+}
+
+// PermanentError marks a worker function error as permanent, so the worker
+// function is not retried.
+type PermanentError struct {
+	err error
+}
+
+// Error implements the error interface and returns the wrapped error
+// message.
+func (err *PermanentError) Error() string {
+	return err.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (err *PermanentError) Unwrap() error {
+	return err.err
+}
+
+// Retryable reports that the error must not be retried.
+func (err *PermanentError) Retryable() bool {
+	return false
+}
+
+// AbortError marks a worker function error as fatal to the whole call: the
+// worker function is not retried, and All cancels the other worker
+// functions with the ReasonAborted reason.
+type AbortError struct {
+	err error
+}
+
+// Error implements the error interface and returns the wrapped error
+// message.
+func (err *AbortError) Error() string {
+	return err.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (err *AbortError) Unwrap() error {
+	return err.err
+}
+
+// Retryable reports that the error must not be retried.
+func (err *AbortError) Retryable() bool {
+	return false
+}
+
+// StopError stops the retries of a worker function with a value that is
+// not a canonical success, such as a soft failure placeholder.
+type StopError struct {
+	value interface{}
+}
+
+// Error implements the error interface.
+func (err *StopError) Error() string {
+	return fmt.Sprintf("worker function stopped with %v", err.value)
+}
+
+// Value returns the value the worker function stopped with.
+func (err *StopError) Value() interface{} {
+	return err.value
+}
+
+// Retryable wraps the error in a *RetryableError. It returns nil if err is
+// nil.
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &RetryableError{err: err}
+}
+
+// Permanent wraps the error in a *PermanentError. It returns nil if err is
+// nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{err: err}
+}
+
+// Abort wraps the error in an *AbortError. It returns nil if err is nil.
+func Abort(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &AbortError{err: err}
+}
+
+// StopWith returns a *StopError with the value. The worker function
+// returning it is not retried, and its result has the value, a nil Err and
+// Stopped set. Unlike Abort, the other worker functions are not cancelled.
+func StopWith(value interface{}) error {
+	return &StopError{value: value}
+}
+
+// RetryAfterError asks for the worker function to be retried after a delay
+// of its choosing, such as the one of an HTTP Retry-After header.
+type RetryAfterError struct {
+	delay time.Duration
+	err   error
+}
+
+// Error implements the error interface and returns the delay with the
+// wrapped error message.
+func (err *RetryAfterError) Error() string {
+	if err.err == nil {
+		return fmt.Sprintf("retry after %v", err.delay)
+	}
+	return fmt.Sprintf("retry after %v : %s", err.delay, err.err)
+}
+
+// Unwrap returns the wrapped error.
+func (err *RetryAfterError) Unwrap() error {
+	return err.err
+}
+
+// Retryable reports that the error can be retried.
+func (err *RetryAfterError) Retryable() bool {
+	return true
+}
+
+// Delay returns how long to wait before retrying.
+func (err *RetryAfterError) Delay() time.Duration {
+	return err.delay
+}
+
+// RetryAfter returns a *RetryAfterError wrapping cause, which may be nil.
+// The worker function is retried after d instead of the retry interval or
+// backoff, for that attempt only. A delay past the end of the context ends
+// the retries when the context does.
+func RetryAfter(d time.Duration, cause error) error {
+	return &RetryAfterError{delay: d, err: cause}
+}
+
+// RetryIf sets the predicate that decides if a worker function error can be
+// retried. The default predicate retries every error except the ones that,
+// or that wrap an error that, have a Retryable method returning false, like
+// *PermanentError and *BreakerOpenError.
+func RetryIf(pred func(err error) bool) Option {
+	return func(o *options) {
+		o.retryIf = pred
+	}
+}
+
+// retryable reports whether the worker function error can be retried.
+func (o *options) retryable(err error) bool {
+	if o.retryIf != nil {
+		return o.retryIf(err)
+	}
+
+	var r interface{ Retryable() bool }
+	if errors.As(err, &r) {
+		return r.Retryable()
+	}
+	return true
+}
+
+// ShouldRetry reports whether Func, with the options, would retry a worker
+// function that returned err, which helps testing a RetryIf predicate. Only
+// the error is classified: the limits on the attempts, like MaxAttempts,
+// and the context are not considered. No error, a *StopError and an
+// *AbortError are never retried, and ErrProgress always is.
+func ShouldRetry(err error, opts ...Option) bool {
+	if err == nil {
+		return false
+	}
+
+	var stop *StopError
+	var abort *AbortError
+	switch {
+	case errors.As(err, &stop), errors.As(err, &abort):
+		return false
+	case errors.Is(err, ErrProgress):
+		return true
+	}
+	return newOptions(opts).retryable(err)
+}
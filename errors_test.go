@@ -0,0 +1,342 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermanent(t *testing.T) {
+	t.Run("stop", func(t *testing.T) {
+		t.Log("Func should stop retrying on a permanent error.")
+		errWork := errors.New("bad request")
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			if calls < 3 {
+				return nil, errors.New("transient")
+			}
+			return nil, retry.Permanent(errWork)
+		}
+		result := retry.Func(context.Background(), time.Nanosecond, worker)
+		if assert.Error(t, result.Err) {
+			var errPermanent *retry.PermanentError
+			assert.True(t, errors.As(result.Err, &errPermanent))
+			assert.True(t, errors.Is(result.Err, errWork))
+			assert.Equal(t, errWork, errors.Unwrap(errPermanent))
+		}
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		t.Log("Permanent and Retryable should return nil for a nil error.")
+		assert.Nil(t, retry.Permanent(nil))
+		assert.Nil(t, retry.Retryable(nil))
+	})
+}
+
+func TestRetryable(t *testing.T) {
+	t.Run("retry", func(t *testing.T) {
+		t.Log("Func should keep retrying a retryable error, even if it wraps a permanent one.")
+		errWork := errors.New("flaky")
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			if calls < 3 {
+				return nil, retry.Retryable(retry.Permanent(errWork))
+			}
+			return "ok", nil
+		}
+		result := retry.Func(context.Background(), time.Nanosecond, worker)
+		assert.NoError(t, result.Err)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, "flaky", retry.Retryable(errWork).Error())
+		assert.True(t, errors.Is(retry.Retryable(errWork), errWork))
+	})
+}
+
+func TestRetryIf(t *testing.T) {
+	t.Run("predicate", func(t *testing.T) {
+		t.Log("Func should use the predicate instead of the default classification.")
+		errStop := errors.New("stop")
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			if calls < 3 {
+				return nil, retry.Permanent(errors.New("retried anyway"))
+			}
+			return nil, errStop
+		}
+		pred := func(err error) bool {
+			return !errors.Is(err, errStop)
+		}
+		result := retry.Func(context.Background(), time.Nanosecond, worker, retry.RetryIf(pred))
+		if assert.Error(t, result.Err) {
+			assert.Equal(t, errStop, errors.Unwrap(result.Err))
+		}
+		assert.Equal(t, 3, calls)
+	})
+}
+
+func TestErrProgress(t *testing.T) {
+	t.Run("reset", func(t *testing.T) {
+		t.Log("Func should not count ErrProgress as a failed attempt.")
+		errNet := errors.New("network error")
+		returns := []error{errNet, errNet, retry.ErrProgress, errNet, errNet, retry.ErrProgress, nil}
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			err := returns[calls]
+			calls++
+			if err != nil {
+				return nil, err
+			}
+			return "downloaded", nil
+		}
+		var numbers []int
+		b := backoffFunc(func(a retry.Attempt) time.Duration {
+			numbers = append(numbers, a.Number)
+			return time.Millisecond
+		})
+		result := retry.Func(context.Background(), time.Millisecond, worker, retry.MaxAttempts(3), retry.UseBackoff(b))
+		assert.NoError(t, result.Err)
+		assert.Equal(t, "downloaded", result.Value)
+		assert.Equal(t, []int{1, 2, 1, 2}, numbers)
+	})
+
+	t.Run("forever", func(t *testing.T) {
+		t.Log("Func should stop making progress when the context times out.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return nil, retry.ErrProgress
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		result := retry.Func(ctx, time.Millisecond, worker, retry.MaxAttempts(1))
+		var err *retry.Error
+		if assert.True(t, errors.As(result.Err, &err)) {
+			assert.Equal(t, retry.ReasonTimeout, err.Reason())
+			assert.True(t, errors.Is(err, retry.ErrProgress))
+		}
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	t.Run("delay", func(t *testing.T) {
+		t.Log("Func should wait the delay asked by the worker function.")
+		errBusy := errors.New("too many requests")
+		var calls []time.Time
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls = append(calls, time.Now())
+			if len(calls) == 1 {
+				return nil, retry.RetryAfter(30*time.Millisecond, errBusy)
+			}
+			return "ok", nil
+		}
+		b := backoffFunc(func(a retry.Attempt) time.Duration {
+			return time.Millisecond
+		})
+		result := retry.Func(context.Background(), time.Millisecond, worker, retry.UseBackoff(b))
+		assert.NoError(t, result.Err)
+		if assert.Len(t, calls, 2) {
+			assert.GreaterOrEqual(t, int64(calls[1].Sub(calls[0])), int64(30*time.Millisecond))
+		}
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		t.Log("Func should stop at the context deadline when the delay is longer.")
+		errBusy := errors.New("too many requests")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return nil, retry.RetryAfter(time.Hour, errBusy)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		result := retry.Func(ctx, time.Millisecond, worker)
+		assert.Less(t, int64(time.Since(start)), int64(time.Second))
+		var err *retry.Error
+		if assert.True(t, errors.As(result.Err, &err)) {
+			assert.True(t, errors.Is(err, errBusy))
+			assert.Regexp(t, `retry after 1h0m0s : too many requests$`, err.Error())
+		}
+	})
+}
+
+func TestAbort(t *testing.T) {
+	t.Run("all", func(t *testing.T) {
+		t.Log("All should cancel the other worker functions when one aborts.")
+		errFatal := errors.New("fatal")
+		started := make(chan struct{}, 2)
+		aborting := func(ctx context.Context) (interface{}, error) {
+			<-started
+			<-started
+			return nil, retry.Abort(errFatal)
+		}
+		pending := func(ctx context.Context) (interface{}, error) {
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+			return nil, errors.New("not yet")
+		}
+		workers := map[string]retry.Worker{"abort": aborting, "pending1": pending, "pending2": pending}
+		results := retry.All(context.Background(), time.Millisecond, workers, retry.MaxGoroutines)
+		var err *retry.Error
+		if assert.True(t, errors.As(results["abort"].Err, &err)) {
+			assert.Equal(t, retry.ReasonAborted, err.Reason())
+			assert.True(t, errors.Is(err, errFatal))
+			assert.Regexp(t, `^worker "abort" aborted after .+ : fatal$`, err.Error())
+		}
+		for _, name := range []string{"pending1", "pending2"} {
+			if assert.True(t, errors.As(results[name].Err, &err)) {
+				assert.Equal(t, retry.ReasonAborted, err.Reason())
+			}
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		t.Log("Abort should return nil for a nil error.")
+		assert.NoError(t, retry.Abort(nil))
+	})
+}
+
+func TestStopWith(t *testing.T) {
+	t.Run("func", func(t *testing.T) {
+		t.Log("Func should stop retrying and return the stop value with the Stopped flag.")
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			if calls < 3 {
+				return nil, errors.New("not yet")
+			}
+			return nil, retry.StopWith("placeholder")
+		}
+		result := retry.Func(context.Background(), time.Millisecond, worker)
+		assert.NoError(t, result.Err)
+		assert.Equal(t, "placeholder", result.Value)
+		assert.True(t, result.Stopped)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Log("Func should not set the Stopped flag for a canonical success.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return "value", nil
+		}
+		result := retry.Func(context.Background(), time.Millisecond, worker)
+		assert.NoError(t, result.Err)
+		assert.False(t, result.Stopped)
+	})
+
+	placeholder := func(ctx context.Context) (interface{}, error) {
+		return nil, retry.StopWith("placeholder")
+	}
+	real := func(ctx context.Context) (interface{}, error) {
+		time.Sleep(10 * time.Millisecond)
+		return "real", nil
+	}
+	failing := func(ctx context.Context) (interface{}, error) {
+		return nil, retry.Permanent(errors.New("down"))
+	}
+
+	t.Run("first", func(t *testing.T) {
+		t.Log("First should not let a stopped placeholder win over a real success.")
+		workers := map[string]retry.Worker{"placeholder": placeholder, "real": real}
+		result := retry.First(context.Background(), time.Millisecond, workers, retry.MaxGoroutines)
+		assert.NoError(t, result.Err)
+		assert.Equal(t, "real", result.Value)
+		assert.False(t, result.Stopped)
+
+		result = retry.FirstAuthoritative(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, func(interface{}) bool { return false })
+		assert.Equal(t, "real", result.Value)
+
+		result = retry.FirstPreferred(context.Background(), time.Millisecond, workers, "placeholder", time.Second)
+		assert.Equal(t, "real", result.Value)
+	})
+
+	t.Run("firstfallback", func(t *testing.T) {
+		t.Log("First should return the stopped placeholder when no worker function succeeds.")
+		workers := map[string]retry.Worker{"placeholder": placeholder, "failing": failing}
+		result := retry.First(context.Background(), time.Millisecond, workers, retry.MaxGoroutines)
+		assert.NoError(t, result.Err)
+		assert.Equal(t, "placeholder", result.Value)
+		assert.True(t, result.Stopped)
+	})
+
+	t.Run("firstn", func(t *testing.T) {
+		t.Log("FirstN should count a stopped placeholder as failed.")
+		workers := map[string]retry.Worker{"placeholder": placeholder, "real": real}
+		results, err := retry.FirstN(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, 2)
+		assert.Empty(t, results)
+		var errRetry *retry.Error
+		if assert.True(t, errors.As(err, &errRetry)) {
+			assert.Equal(t, retry.ReasonFailFast, errRetry.Reason())
+		}
+	})
+
+	t.Run("count", func(t *testing.T) {
+		t.Log("CountSuccesses should not count a stopped placeholder.")
+		workers := map[string]retry.Worker{"placeholder": placeholder, "real": real}
+		results := retry.All(context.Background(), time.Millisecond, workers, retry.MaxGoroutines)
+		assert.Equal(t, 1, retry.CountSuccesses(results))
+		assert.False(t, results["placeholder"].Success())
+	})
+
+	t.Run("wrapped", func(t *testing.T) {
+		t.Log("Func should stop on a wrapped *StopError.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return nil, fmt.Errorf("soft failure: %w", retry.StopWith(42))
+		}
+		result := retry.Func(context.Background(), time.Millisecond, worker)
+		assert.NoError(t, result.Err)
+		assert.Equal(t, 42, result.Value)
+		assert.True(t, result.Stopped)
+	})
+}
+
+func TestShouldRetry(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Log("ShouldRetry should classify the errors like Func does by default.")
+		errWork := errors.New("some error")
+		assert.True(t, retry.ShouldRetry(errWork))
+		assert.True(t, retry.ShouldRetry(retry.Retryable(errWork)))
+		assert.True(t, retry.ShouldRetry(retry.ErrProgress))
+		assert.True(t, retry.ShouldRetry(retry.RetryAfter(time.Second, errWork)))
+		assert.False(t, retry.ShouldRetry(nil))
+		assert.False(t, retry.ShouldRetry(retry.Permanent(errWork)))
+		assert.False(t, retry.ShouldRetry(fmt.Errorf("wrapped: %w", retry.Permanent(errWork))))
+		assert.False(t, retry.ShouldRetry(retry.Abort(errWork)))
+		assert.False(t, retry.ShouldRetry(retry.StopWith("value")))
+	})
+
+	t.Run("predicate", func(t *testing.T) {
+		t.Log("ShouldRetry should apply the RetryIf predicate.")
+		errTemporary := errors.New("temporary")
+		temporary := retry.RetryIf(func(err error) bool {
+			return errors.Is(err, errTemporary)
+		})
+		assert.True(t, retry.ShouldRetry(errTemporary, temporary))
+		assert.False(t, retry.ShouldRetry(errors.New("other"), temporary))
+		assert.False(t, retry.ShouldRetry(retry.Abort(errTemporary), temporary))
+	})
+
+	t.Run("func", func(t *testing.T) {
+		t.Log("ShouldRetry should agree with what Func does.")
+		errs := []error{errors.New("some error"), retry.Permanent(errors.New("bad")), retry.Abort(errors.New("fatal"))}
+		for _, errWork := range errs {
+			var calls int
+			worker := func(ctx context.Context) (interface{}, error) {
+				calls++
+				if calls > 1 {
+					return nil, nil
+				}
+				return nil, errWork
+			}
+			retry.Func(context.Background(), time.Millisecond, worker)
+			assert.Equal(t, retry.ShouldRetry(errWork), calls > 1, errWork.Error())
+		}
+	})
+}
@@ -0,0 +1,158 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// extend is the configuration set by ExtendOnProgress.
+type extend struct {
+	increment time.Duration
+	max       time.Duration
+}
+
+// ExtendOnProgress gives each worker function a deadline of increment,
+// pushed forward by increment every time it returns ErrProgress, so a
+// worker function making steady progress is not cut off mid-stream. The
+// deadline never goes past max since the work started, which must be
+// positive, and the context passed in still bounds the whole work. The
+// worker function that misses the deadline times out with the
+// ReasonTimeout reason.
+func ExtendOnProgress(increment, max time.Duration) Option {
+	if max <= 0 {
+		panic("retry: ExtendOnProgress needs a positive max")
+	}
+	return func(o *options) {
+		o.extend = &extend{increment: increment, max: max}
+	}
+}
+
+// extensionKey is the context key used to store the extension.
+type extensionKey struct{}
+
+// extension is a context whose deadline moves forward on progress, up to a
+// hard limit. It ends when the deadline passes, when its parent ends or when
+// it is stopped.
+type extension struct {
+	context.Context
+	increment time.Duration
+	limit     time.Time
+	timer     *time.Timer
+	done      chan struct{}
+
+	mu       sync.Mutex
+	deadline time.Time
+	err      error
+	expired  bool
+}
+
+// with returns a context for the work of a worker function, ending at the
+// first deadline, and the function that releases it.
+func (x *extend) with(ctx context.Context) (*extension, func()) {
+	now := time.Now()
+	e := extension{
+		Context:   ctx,
+		increment: x.increment,
+		limit:     now.Add(x.max),
+		done:      make(chan struct{}),
+	}
+	e.deadline = e.next(now)
+	e.timer = time.AfterFunc(e.deadline.Sub(now), e.expire)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			e.end(ctx.Err(), false)
+		case <-e.done:
+		}
+	}()
+
+	return &e, func() {
+		e.timer.Stop()
+		e.end(context.Canceled, false)
+	}
+}
+
+// next returns the deadline starting at now, capped at the limit.
+func (e *extension) next(now time.Time) time.Time {
+	deadline := now.Add(e.increment)
+	if deadline.After(e.limit) {
+		return e.limit
+	}
+	return deadline
+}
+
+// progress pushes the deadline forward.
+func (e *extension) progress() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.err != nil {
+		return
+	}
+	now := time.Now()
+	e.deadline = e.next(now)
+	e.timer.Reset(e.deadline.Sub(now))
+}
+
+// expire ends the context if the deadline passed. The deadline may have
+// moved forward since the timer fired, in which case the timer was reset.
+func (e *extension) expire() {
+	e.mu.Lock()
+	passed := !time.Now().Before(e.deadline)
+	e.mu.Unlock()
+	if passed {
+		e.end(context.DeadlineExceeded, true)
+	}
+}
+
+// end ends the context with the error, unless it already ended.
+func (e *extension) end(err error, expired bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.err != nil {
+		return
+	}
+	e.err = err
+	e.expired = expired
+	close(e.done)
+}
+
+// hasExpired reports whether the context ended because the deadline passed.
+func (e *extension) hasExpired() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.expired
+}
+
+// Deadline implements the context.Context interface, returning the current
+// deadline or the deadline of the parent, whichever comes first.
+func (e *extension) Deadline() (time.Time, bool) {
+	e.mu.Lock()
+	deadline := e.deadline
+	e.mu.Unlock()
+	if parent, ok := e.Context.Deadline(); ok && parent.Before(deadline) {
+		return parent, true
+	}
+	return deadline, true
+}
+
+// Done implements the context.Context interface.
+func (e *extension) Done() <-chan struct{} {
+	return e.done
+}
+
+// Err implements the context.Context interface.
+func (e *extension) Err() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.err
+}
+
+// Value implements the context.Context interface.
+func (e *extension) Value(key interface{}) interface{} {
+	if key == (extensionKey{}) {
+		return e
+	}
+	return e.Context.Value(key)
+}
@@ -0,0 +1,93 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtendOnProgress(t *testing.T) {
+	t.Run("progress", func(t *testing.T) {
+		t.Log("Func should keep a worker function making progress alive past the increment.")
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			time.Sleep(5 * time.Millisecond)
+			if calls < 10 {
+				return nil, retry.ErrProgress
+			}
+			return "done", nil
+		}
+		start := time.Now()
+		result := retry.Func(context.Background(), time.Millisecond, worker, retry.ExtendOnProgress(20*time.Millisecond, time.Second))
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, "done", result.Value)
+		}
+		assert.Greater(t, int64(time.Since(start)), int64(20*time.Millisecond))
+	})
+
+	t.Run("stall", func(t *testing.T) {
+		t.Log("Func should time out a worker function that stops making progress.")
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			if calls < 3 {
+				return nil, retry.ErrProgress
+			}
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		start := time.Now()
+		result := retry.Func(context.Background(), time.Millisecond, worker, retry.ExtendOnProgress(20*time.Millisecond, time.Second))
+		var err *retry.Error
+		if assert.True(t, errors.As(result.Err, &err)) {
+			assert.Equal(t, retry.ReasonTimeout, err.Reason())
+			assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		}
+		assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	})
+
+	t.Run("max", func(t *testing.T) {
+		t.Log("Func should time out a worker function that keeps making progress past the max.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			time.Sleep(time.Millisecond)
+			return nil, retry.ErrProgress
+		}
+		start := time.Now()
+		result := retry.Func(context.Background(), time.Millisecond, worker, retry.ExtendOnProgress(20*time.Millisecond, 50*time.Millisecond))
+		var err *retry.Error
+		if assert.True(t, errors.As(result.Err, &err)) {
+			assert.Equal(t, retry.ReasonTimeout, err.Reason())
+		}
+		assert.GreaterOrEqual(t, int64(time.Since(start)), int64(50*time.Millisecond))
+		assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	})
+
+	t.Run("losers", func(t *testing.T) {
+		t.Log("First should not report the losers cancelled under ExtendOnProgress as timed out.")
+		winner := func(ctx context.Context) (interface{}, error) {
+			return "winner", nil
+		}
+		loser := func(ctx context.Context) (interface{}, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		var timeouts int
+		workers := map[string]retry.Worker{"winner": winner, "loser": loser}
+		result := retry.First(context.Background(), time.Millisecond, workers, retry.MaxGoroutines,
+			retry.ExtendOnProgress(time.Second, time.Second),
+			retry.OnTimeout(func(error) { timeouts++ }),
+			retry.LoserGrace(time.Second))
+		assert.NoError(t, result.Err)
+		assert.Equal(t, 0, timeouts)
+	})
+
+	t.Run("nomax", func(t *testing.T) {
+		t.Log("ExtendOnProgress should require a positive max.")
+		assert.Panics(t, func() { retry.ExtendOnProgress(time.Second, 0) })
+	})
+}
@@ -0,0 +1,52 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// FallbackStep is a worker function tried by Fallback, with how long to
+// keep retrying it before moving on to the next step.
+type FallbackStep struct {
+	// Worker is the worker function to retry.
+	Worker Worker
+
+	// Interval is the time to wait between attempts.
+	Interval time.Duration
+
+	// Timeout is how long the step is retried. Zero means until the
+	// worker function stops being retried or the context ends.
+	Timeout time.Duration
+}
+
+// errNoSteps is returned by Fallback when there are no steps to try.
+var errNoSteps = errors.New("no fallback steps")
+
+// Fallback retries the steps one after the other, moving on to the next
+// step when a step fails, and returns the result of the first step that
+// succeeds. When all the steps fail, or the context ends, the error of the
+// last step tried is returned. The options are applied to all the steps.
+func Fallback(ctx context.Context, steps []FallbackStep, opts ...Option) Result {
+	result := Result{Err: errNoSteps}
+	for i, step := range steps {
+		if i > 0 && ctx.Err() != nil {
+			break
+		}
+		result = fallbackStep(ctx, step, opts)
+		if result.Err == nil {
+			break
+		}
+	}
+	return result
+}
+
+// fallbackStep retries the worker function of the step for its timeout.
+func fallbackStep(ctx context.Context, step FallbackStep, opts []Option) Result {
+	if step.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, step.Timeout)
+		defer cancel()
+	}
+	return Func(ctx, step.Interval, step.Worker, opts...)
+}
@@ -0,0 +1,78 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFallback(t *testing.T) {
+	errDown := errors.New("down")
+	fail := func(ctx context.Context) (interface{}, error) {
+		return nil, errDown
+	}
+
+	t.Run("next", func(t *testing.T) {
+		t.Log("Fallback should return the value of the first step that succeeds.")
+		var calls int
+		b := func(ctx context.Context) (interface{}, error) {
+			calls++
+			return "B", nil
+		}
+		c := func(ctx context.Context) (interface{}, error) {
+			return "C", nil
+		}
+		steps := []retry.FallbackStep{
+			{Worker: fail, Interval: time.Millisecond, Timeout: 10 * time.Millisecond},
+			{Worker: b, Interval: time.Millisecond, Timeout: 10 * time.Millisecond},
+			{Worker: c, Interval: time.Millisecond, Timeout: 10 * time.Millisecond},
+		}
+		result := retry.Fallback(context.Background(), steps)
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, "B", result.Value)
+		}
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("allfail", func(t *testing.T) {
+		t.Log("Fallback should return the error of the last step.")
+		errLast := errors.New("last down")
+		last := func(ctx context.Context) (interface{}, error) {
+			return nil, retry.Permanent(errLast)
+		}
+		steps := []retry.FallbackStep{
+			{Worker: fail, Interval: time.Millisecond, Timeout: 5 * time.Millisecond},
+			{Worker: last, Interval: time.Millisecond},
+		}
+		result := retry.Fallback(context.Background(), steps)
+		assert.True(t, errors.Is(result.Err, errLast))
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		t.Log("Fallback should not try more steps once the context ends.")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		var calls int
+		next := func(ctx context.Context) (interface{}, error) {
+			calls++
+			return "next", nil
+		}
+		steps := []retry.FallbackStep{
+			{Worker: fail, Interval: time.Millisecond},
+			{Worker: next, Interval: time.Millisecond},
+		}
+		result := retry.Fallback(ctx, steps)
+		assert.True(t, errors.Is(result.Err, errDown))
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("nosteps", func(t *testing.T) {
+		t.Log("Fallback should return an error without steps.")
+		result := retry.Fallback(context.Background(), nil)
+		assert.EqualError(t, result.Err, "no fallback steps")
+	})
+}
@@ -0,0 +1,46 @@
+package retry
+
+import "sync/atomic"
+
+// Gauge counts the worker function calls running at the same time, and
+// the peak of that count, for tuning maxGs. The zero value is ready to use
+// and a Gauge may be shared by many calls.
+type Gauge struct {
+	running int64
+	peak    int64
+}
+
+// TrackConcurrency makes the gauge count the worker function calls while
+// they run. The time spent waiting to retry is not counted.
+func TrackConcurrency(g *Gauge) Option {
+	return func(o *options) {
+		o.gauge = g
+	}
+}
+
+// Running returns how many worker function calls are running.
+func (g *Gauge) Running() int {
+	return int(atomic.LoadInt64(&g.running))
+}
+
+// Peak returns the most worker function calls that ran at the same time.
+func (g *Gauge) Peak() int {
+	return int(atomic.LoadInt64(&g.peak))
+}
+
+// track counts a worker function call, returning the release function that
+// also stops counting it.
+func (g *Gauge) track(release func()) func() {
+	running := atomic.AddInt64(&g.running, 1)
+	for {
+		peak := atomic.LoadInt64(&g.peak)
+		if running <= peak || atomic.CompareAndSwapInt64(&g.peak, peak, running) {
+			break
+		}
+	}
+
+	return func() {
+		atomic.AddInt64(&g.running, -1)
+		release()
+	}
+}
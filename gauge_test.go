@@ -0,0 +1,50 @@
+package retry_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackConcurrency(t *testing.T) {
+	t.Run("pool", func(t *testing.T) {
+		t.Log("All should run at most maxGs worker functions at the same time.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			time.Sleep(10 * time.Millisecond)
+			return "ok", nil
+		}
+		workers := make(map[string]retry.Worker)
+		for i := 0; i < 10; i++ {
+			workers[fmt.Sprint("worker", i)] = worker
+		}
+		var g retry.Gauge
+		results := retry.All(context.Background(), time.Millisecond, workers, 3, retry.TrackConcurrency(&g))
+		assert.Equal(t, 10, retry.CountSuccesses(results))
+		assert.Equal(t, 3, g.Peak())
+		assert.Equal(t, 0, g.Running())
+	})
+
+	t.Run("unbounded", func(t *testing.T) {
+		t.Log("All should run all the worker functions at the same time without maxGs.")
+		var barrier sync.WaitGroup
+		barrier.Add(10)
+		worker := func(ctx context.Context) (interface{}, error) {
+			barrier.Done()
+			barrier.Wait()
+			return "ok", nil
+		}
+		workers := make(map[string]retry.Worker)
+		for i := 0; i < 10; i++ {
+			workers[fmt.Sprint("worker", i)] = worker
+		}
+		var g retry.Gauge
+		results := retry.All(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, retry.TrackConcurrency(&g))
+		assert.Equal(t, 10, retry.CountSuccesses(results))
+		assert.Equal(t, 10, g.Peak())
+	})
+}
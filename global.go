@@ -0,0 +1,66 @@
+package retry
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// semaphore limits how many worker function calls run at the same time.
+type semaphore struct {
+	slots chan struct{}
+}
+
+// global holds the *semaphore set by SetGlobalConcurrency, nil meaning no
+// limit.
+var global atomic.Value
+
+func init() {
+	global.Store((*semaphore)(nil))
+}
+
+// SetGlobalConcurrency limits how many worker function calls run at the same
+// time across every Func, All and First call of the process. The limit is
+// acquired for each attempt and released as soon as the worker function
+// returns, never while waiting to retry, so a First with more worker
+// functions than n still makes progress. Zero or less means no limit, the
+// default. Attempts already waiting keep the limit they started with. The
+// calls made from within a worker function attempt share its slot.
+func SetGlobalConcurrency(n int) {
+	if n <= 0 {
+		global.Store((*semaphore)(nil))
+		return
+	}
+	global.Store(&semaphore{slots: make(chan struct{}, n)})
+}
+
+// globalKey is the context key used to store the *semaphore whose slot the
+// worker function call holds.
+type globalKey struct{}
+
+// acquireGlobal waits for a slot of the global limit. It returns false if the
+// context ends first, otherwise a copy of the context marked as holding the
+// slot, and the function that releases the slot. A worker function calling
+// Func within its attempt already holds a slot, so the nested attempts do
+// not wait for another one, which could never come with a limit of one.
+func acquireGlobal(ctx context.Context) (context.Context, func(), bool) {
+	sem := global.Load().(*semaphore)
+	if sem == nil || ctx.Value(globalKey{}) == sem {
+		return ctx, func() {}, true
+	}
+	release, ok := acquire(ctx, sem.slots)
+	if !ok {
+		return nil, nil, false
+	}
+	return context.WithValue(ctx, globalKey{}, sem), release, true
+}
+
+// acquire waits for one of the slots. It returns false if the context ends
+// first, otherwise the function that releases the slot.
+func acquire(ctx context.Context, slots chan struct{}) (func(), bool) {
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, true
+	case <-ctx.Done():
+		return nil, false
+	}
+}
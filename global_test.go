@@ -0,0 +1,95 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetGlobalConcurrency(t *testing.T) {
+	t.Run("cap", func(t *testing.T) {
+		t.Log("The global limit should be respected across concurrent All calls.")
+		retry.SetGlobalConcurrency(2)
+		defer retry.SetGlobalConcurrency(0)
+
+		var running, peak int32
+		worker := func(ctx context.Context) (interface{}, error) {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			return nil, errors.New("not yet")
+		}
+		workers := make(map[string]retry.Worker)
+		for i := 0; i < 3; i++ {
+			workers[fmt.Sprint("worker", i)] = worker
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		var wg sync.WaitGroup
+		wg.Add(2)
+		for i := 0; i < 2; i++ {
+			go func() {
+				defer wg.Done()
+				results := retry.All(ctx, time.Millisecond, workers, retry.MaxGoroutines)
+				assert.Len(t, results, 3)
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(2), atomic.LoadInt32(&peak))
+	})
+
+	t.Run("first", func(t *testing.T) {
+		t.Log("First should not block forever with more worker functions than the global limit.")
+		retry.SetGlobalConcurrency(1)
+		defer retry.SetGlobalConcurrency(0)
+
+		var calls int32
+		worker := func(ctx context.Context) (interface{}, error) {
+			if atomic.AddInt32(&calls, 1) < 5 {
+				return nil, errors.New("not yet")
+			}
+			return "ok", nil
+		}
+		workers := map[string]retry.Worker{"worker1": worker, "worker2": worker, "worker3": worker}
+		result := retry.First(context.Background(), time.Millisecond, workers, retry.MaxGoroutines)
+		assert.NoError(t, result.Err)
+	})
+	t.Run("nested", func(t *testing.T) {
+		t.Log("A worker function calling Func should not wait for another slot of the global limit.")
+		retry.SetGlobalConcurrency(1)
+		defer retry.SetGlobalConcurrency(0)
+
+		inner := func(ctx context.Context) (interface{}, error) {
+			return "inner", nil
+		}
+		outer := func(ctx context.Context) (interface{}, error) {
+			return retry.FuncValue(ctx, time.Millisecond, inner)
+		}
+		done := make(chan retry.Result, 1)
+		go func() {
+			done <- retry.Func(context.Background(), time.Millisecond, outer)
+		}()
+		select {
+		case result := <-done:
+			if assert.NoError(t, result.Err) {
+				assert.Equal(t, "inner", result.Value)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("nested Func blocked on the global limit")
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrSkipped is the error of a Graph worker function that did not run
+// because one of its prerequisites failed.
+var ErrSkipped = errors.New("prerequisite worker function failed")
+
+// GraphNode is a worker function run by Graph once the worker functions it
+// depends on succeeded.
+type GraphNode struct {
+	// Worker is the worker function to retry.
+	Worker Worker
+
+	// DependsOn holds the names of the worker functions that must succeed
+	// before this one runs.
+	DependsOn []string
+}
+
+// Graph calls each worker function every retry interval until it succeeds
+// or the context ends, like All, but only once the worker functions it
+// depends on succeeded. Worker functions without pending prerequisites run
+// at the same time. A worker function whose prerequisite failed is skipped,
+// with an error wrapping ErrSkipped. Graph returns an error, and no
+// results, when a node depends on an unknown one or the dependencies form a
+// cycle.
+func Graph(ctx context.Context, retryInterval time.Duration, nodes map[string]GraphNode, opts ...Option) (map[string]Result, error) {
+	if err := checkGraph(nodes); err != nil {
+		return nil, err
+	}
+
+	o := newOptions(opts)
+	defer o.finished(o.now())
+
+	done := make(map[string]chan struct{}, len(nodes))
+	for name := range nodes {
+		done[name] = make(chan struct{})
+	}
+
+	results := make(map[string]Result, len(nodes))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	wg.Add(len(nodes))
+	for name, node := range nodes {
+		name, node := name, node
+		go func() {
+			defer wg.Done()
+			defer close(done[name])
+
+			for _, dep := range node.DependsOn {
+				<-done[dep]
+				mu.Lock()
+				failed := results[dep].Err != nil
+				mu.Unlock()
+				if failed {
+					mu.Lock()
+					results[name] = Result{Err: fmt.Errorf("%w : %q", ErrSkipped, dep)}
+					mu.Unlock()
+					return
+				}
+			}
+
+			result := work(ctx, name, retryInterval, node.Worker, o)
+			mu.Lock()
+			results[name] = result
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+// checkGraph returns an error if a node depends on an unknown one or the
+// dependencies form a cycle.
+func checkGraph(nodes map[string]GraphNode) error {
+	names := make([]string, 0, len(nodes))
+	for name, node := range nodes {
+		names = append(names, name)
+		for _, dep := range node.DependsOn {
+			if _, ok := nodes[dep]; !ok {
+				return fmt.Errorf("worker %q depends on unknown worker %q", name, dep)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(nodes))
+	var path []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			for i, n := range path {
+				if n == name {
+					return fmt.Errorf("dependency cycle: %s", strings.Join(append(path[i:], name), " -> "))
+				}
+			}
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range nodes[name].DependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
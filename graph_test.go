@@ -0,0 +1,107 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraph(t *testing.T) {
+	t.Run("diamond", func(t *testing.T) {
+		t.Log("Graph should run each worker function after its prerequisites, and the independent ones at the same time.")
+		var mu sync.Mutex
+		finished := make(map[string]time.Time)
+		started := make(map[string]time.Time)
+		worker := func(name string) retry.Worker {
+			return func(ctx context.Context) (interface{}, error) {
+				mu.Lock()
+				started[name] = time.Now()
+				mu.Unlock()
+				time.Sleep(10 * time.Millisecond)
+				mu.Lock()
+				finished[name] = time.Now()
+				mu.Unlock()
+				return name, nil
+			}
+		}
+		nodes := map[string]retry.GraphNode{
+			"network": {Worker: worker("network")},
+			"db":      {Worker: worker("db"), DependsOn: []string{"network"}},
+			"cache":   {Worker: worker("cache"), DependsOn: []string{"network"}},
+			"app":     {Worker: worker("app"), DependsOn: []string{"db", "cache"}},
+		}
+		results, err := retry.Graph(context.Background(), time.Millisecond, nodes)
+		assert.NoError(t, err)
+		assert.Len(t, results, 4)
+		for name, result := range results {
+			if assert.NoError(t, result.Err) {
+				assert.Equal(t, name, result.Value)
+			}
+		}
+		assert.False(t, started["db"].Before(finished["network"]))
+		assert.False(t, started["cache"].Before(finished["network"]))
+		assert.False(t, started["app"].Before(finished["db"]))
+		assert.False(t, started["app"].Before(finished["cache"]))
+		assert.True(t, started["cache"].Before(finished["db"]), "db and cache should overlap")
+	})
+
+	t.Run("skipped", func(t *testing.T) {
+		t.Log("Graph should skip the worker functions whose prerequisite failed.")
+		var calls int
+		ok := func(ctx context.Context) (interface{}, error) {
+			return nil, nil
+		}
+		fail := func(ctx context.Context) (interface{}, error) {
+			return nil, retry.Permanent(errors.New("db down"))
+		}
+		app := func(ctx context.Context) (interface{}, error) {
+			calls++
+			return nil, nil
+		}
+		nodes := map[string]retry.GraphNode{
+			"network": {Worker: ok},
+			"db":      {Worker: fail, DependsOn: []string{"network"}},
+			"cache":   {Worker: ok, DependsOn: []string{"network"}},
+			"app":     {Worker: app, DependsOn: []string{"db", "cache"}},
+		}
+		results, err := retry.Graph(context.Background(), time.Millisecond, nodes)
+		assert.NoError(t, err)
+		assert.NoError(t, results["cache"].Err)
+		assert.Error(t, results["db"].Err)
+		assert.True(t, errors.Is(results["app"].Err, retry.ErrSkipped))
+		assert.EqualError(t, results["app"].Err, `prerequisite worker function failed : "db"`)
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		t.Log("Graph should return an error when the dependencies form a cycle.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			t.Error("worker function called")
+			return nil, nil
+		}
+		nodes := map[string]retry.GraphNode{
+			"a": {Worker: worker, DependsOn: []string{"c"}},
+			"b": {Worker: worker, DependsOn: []string{"a"}},
+			"c": {Worker: worker, DependsOn: []string{"b"}},
+			"d": {Worker: worker},
+		}
+		results, err := retry.Graph(context.Background(), time.Millisecond, nodes)
+		assert.EqualError(t, err, "dependency cycle: a -> c -> b -> a")
+		assert.Nil(t, results)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		t.Log("Graph should return an error when a worker function depends on an unknown one.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return nil, nil
+		}
+		nodes := map[string]retry.GraphNode{"a": {Worker: worker, DependsOn: []string{"b"}}}
+		_, err := retry.Graph(context.Background(), time.Millisecond, nodes)
+		assert.EqualError(t, err, `worker "a" depends on unknown worker "b"`)
+	})
+}
@@ -0,0 +1,76 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Group retries worker functions added one at a time, in the way of
+// errgroup: Go starts retrying a worker function right away, and Wait
+// returns the results of all of them. The worker functions share the
+// context, retry interval and options of the group.
+type Group struct {
+	ctx           context.Context
+	cancel        func(Reason)
+	retryInterval time.Duration
+	o             *options
+	start         time.Time
+	wg            sync.WaitGroup
+
+	mu      sync.Mutex
+	results map[string]Result
+}
+
+// NewGroup returns a group retrying its worker functions every retry
+// interval until they succeed or the context ends, like All. A worker
+// function returning an error made by Abort cancels the others, and so does
+// a permanent error with FailFast. Wait must be called to release the group
+// resources.
+func NewGroup(ctx context.Context, retryInterval time.Duration, opts ...Option) *Group {
+	o := newOptions(opts)
+	ctx, cancel := withCancel(ctx)
+	return &Group{
+		ctx:           ctx,
+		cancel:        cancel,
+		retryInterval: retryInterval,
+		o:             o,
+		start:         o.now(),
+		results:       make(map[string]Result),
+	}
+}
+
+// Go starts retrying the worker function in its own goroutine. The names
+// must be unique in the group, and Go must not be called after Wait.
+func (g *Group) Go(name string, worker Worker) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		result := work(g.ctx, name, g.retryInterval, worker, g.o)
+
+		g.mu.Lock()
+		g.results[name] = result
+		g.mu.Unlock()
+
+		switch errorReason(result.Err) {
+		case ReasonPermanent:
+			if g.o.failFast {
+				g.cancel(ReasonFailFast)
+			}
+		case ReasonAborted:
+			g.cancel(ReasonAborted)
+		}
+	}()
+}
+
+// Wait waits for all the worker functions started by Go to complete, and
+// returns their results by name.
+func (g *Group) Wait() map[string]Result {
+	g.wg.Wait()
+	g.cancel(ReasonCanceled)
+	g.o.finished(g.start)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.results
+}
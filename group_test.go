@@ -0,0 +1,68 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroup(t *testing.T) {
+	t.Run("wait", func(t *testing.T) {
+		t.Log("Wait should return the results of all the worker functions added with Go.")
+		g := retry.NewGroup(context.Background(), time.Millisecond)
+		for _, name := range []string{"a", "b", "c"} {
+			name := name
+			var calls int
+			g.Go(name, func(ctx context.Context) (interface{}, error) {
+				calls++
+				if calls < 2 {
+					return nil, errors.New("not yet")
+				}
+				return name, nil
+			})
+		}
+		results := g.Wait()
+		assert.Len(t, results, 3)
+		for name, result := range results {
+			if assert.NoError(t, result.Err) {
+				assert.Equal(t, name, result.Value)
+			}
+		}
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		t.Log("A worker function aborting should cancel the other worker functions of the group.")
+		g := retry.NewGroup(context.Background(), time.Millisecond)
+		g.Go("abort", func(ctx context.Context) (interface{}, error) {
+			return nil, retry.Abort(errors.New("fatal"))
+		})
+		g.Go("wait", func(ctx context.Context) (interface{}, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		results := g.Wait()
+		var err *retry.Error
+		if assert.True(t, errors.As(results["wait"].Err, &err)) {
+			assert.Equal(t, retry.ReasonAborted, err.Reason())
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Log("The worker functions of the group should time out with the group context.")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		g := retry.NewGroup(ctx, time.Millisecond)
+		g.Go("fail", func(ctx context.Context) (interface{}, error) {
+			return nil, errors.New("down")
+		})
+		results := g.Wait()
+		var err *retry.Error
+		if assert.True(t, errors.As(results["fail"].Err, &err)) {
+			assert.Equal(t, retry.ReasonTimeout, err.Reason())
+		}
+	})
+}
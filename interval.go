@@ -0,0 +1,54 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultInterval is the retry interval used by FuncCtx when the context
+// does not carry one.
+const DefaultInterval = 100 * time.Millisecond
+
+// intervalKey is the context key used to store the default retry interval.
+type intervalKey struct{}
+
+// WithDefaultInterval returns a copy of the context carrying d as the retry
+// interval of FuncCtx.
+func WithDefaultInterval(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, intervalKey{}, d)
+}
+
+// FuncCtx is like Func, but retries every interval set in the context by
+// WithDefaultInterval, or every DefaultInterval if there is none.
+func FuncCtx(ctx context.Context, worker Worker, opts ...Option) Result {
+	retryInterval, ok := ctx.Value(intervalKey{}).(time.Duration)
+	if !ok {
+		retryInterval = DefaultInterval
+	}
+	return Func(ctx, retryInterval, worker, opts...)
+}
+
+// ProportionalInterval makes the retry interval the time left before the
+// context deadline when the work starts, divided by n, so short and long
+// deadlines both get about n attempts. Without a deadline the retry
+// interval is used as is.
+func ProportionalInterval(n int) Option {
+	return func(o *options) {
+		o.proportional = n
+	}
+}
+
+// interval returns the retry interval of the work starting now.
+func (o *options) interval(ctx context.Context, retryInterval time.Duration) time.Duration {
+	if o.proportional < 1 {
+		return retryInterval
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return retryInterval
+	}
+	if left := time.Until(deadline); left > 0 {
+		return left / time.Duration(o.proportional)
+	}
+	return retryInterval
+}
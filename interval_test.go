@@ -0,0 +1,83 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuncCtx(t *testing.T) {
+	intervals := func(calls *[]time.Time) retry.Worker {
+		return func(ctx context.Context) (interface{}, error) {
+			*calls = append(*calls, time.Now())
+			if len(*calls) < 2 {
+				return nil, errors.New("not yet")
+			}
+			return "ok", nil
+		}
+	}
+
+	t.Run("context", func(t *testing.T) {
+		t.Log("FuncCtx should retry every interval set in the context.")
+		var calls []time.Time
+		ctx := retry.WithDefaultInterval(context.Background(), 20*time.Millisecond)
+		result := retry.FuncCtx(ctx, intervals(&calls))
+		assert.NoError(t, result.Err)
+		if assert.Len(t, calls, 2) {
+			wait := calls[1].Sub(calls[0])
+			assert.GreaterOrEqual(t, int64(wait), int64(20*time.Millisecond))
+			assert.Less(t, int64(wait), int64(retry.DefaultInterval))
+		}
+	})
+
+	t.Run("default", func(t *testing.T) {
+		t.Log("FuncCtx should retry every DefaultInterval without an interval in the context.")
+		var calls []time.Time
+		result := retry.FuncCtx(context.Background(), intervals(&calls))
+		assert.NoError(t, result.Err)
+		if assert.Len(t, calls, 2) {
+			assert.GreaterOrEqual(t, int64(calls[1].Sub(calls[0])), int64(retry.DefaultInterval))
+		}
+	})
+}
+
+func TestProportionalInterval(t *testing.T) {
+	count := func(d time.Duration) int {
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			return nil, errors.New("not yet")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), d)
+		defer cancel()
+		retry.Func(ctx, time.Hour, worker, retry.ProportionalInterval(5))
+		return calls
+	}
+
+	t.Run("deadlines", func(t *testing.T) {
+		t.Log("Func should make about as many attempts for a short deadline as for a long one.")
+		short, long := count(50*time.Millisecond), count(500*time.Millisecond)
+		assert.InDelta(t, 5, short, 2)
+		assert.InDelta(t, 5, long, 2)
+	})
+
+	t.Run("nodeadline", func(t *testing.T) {
+		t.Log("Func should use the retry interval when the context has no deadline.")
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			if calls < 3 {
+				return nil, errors.New("not yet")
+			}
+			return nil, nil
+		}
+		start := time.Now()
+		result := retry.Func(context.Background(), 10*time.Millisecond, worker, retry.ProportionalInterval(1000))
+		assert.NoError(t, result.Err)
+		assert.GreaterOrEqual(t, int64(time.Since(start)), int64(20*time.Millisecond))
+	})
+}
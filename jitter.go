@@ -0,0 +1,81 @@
+package retry
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// jitter draws random durations from a source shared by the worker
+// functions.
+type jitter struct {
+	max time.Duration
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// JitteredStart delays the first attempt of each worker function by a random
+// duration between 0 and max, so processes starting at the same time do not
+// call their worker functions in lockstep. The durations are drawn from src,
+// or from a source seeded with the current time if src is nil. The context
+// ending during the delay returns an error without calling the worker
+// function.
+func JitteredStart(max time.Duration, src rand.Source) Option {
+	var j *jitter
+	if max > 0 {
+		if src == nil {
+			src = rand.NewSource(time.Now().UnixNano())
+		}
+		j = &jitter{max: max, rnd: rand.New(src)}
+	}
+	return func(o *options) {
+		o.jitter = j
+	}
+}
+
+// delay returns a random duration between 0 and max.
+func (j *jitter) delay() time.Duration {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return time.Duration(j.rnd.Int63n(int64(j.max)))
+}
+
+// upTo returns a random duration between 0 and d, both included.
+func (j *jitter) upTo(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return time.Duration(j.rnd.Int63n(int64(d) + 1))
+}
+
+// fullJitter implements FullJitter, keeping the cap in the jitter max.
+type fullJitter struct {
+	base time.Duration
+	j    *jitter
+}
+
+// FullJitter returns the "full jitter" exponential Backoff: it waits a
+// random duration between 0 and base doubled for each attempt after the
+// first, capped at max. The durations are drawn from src, or from a source
+// seeded with the current time if src is nil. It can be shared by the
+// worker functions of All and First.
+func FullJitter(base, max time.Duration, src rand.Source) Backoff {
+	if src == nil {
+		src = rand.NewSource(time.Now().UnixNano())
+	}
+	return &fullJitter{base: base, j: &jitter{max: max, rnd: rand.New(src)}}
+}
+
+// Next implements the Backoff interface.
+func (b *fullJitter) Next(a Attempt) time.Duration {
+	ceiling := b.j.max
+	if n := a.Number - 1; n >= 0 && n < 63 {
+		if d := b.base << uint(n); d>>uint(n) == b.base && d < ceiling {
+			ceiling = d
+		}
+	}
+	return b.j.upTo(ceiling)
+}
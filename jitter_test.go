@@ -0,0 +1,93 @@
+package retry_test
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJitteredStart(t *testing.T) {
+	t.Run("delay", func(t *testing.T) {
+		t.Log("Func should delay the first attempt by a seeded random duration within max.")
+		const seed = 42
+		max := 20 * time.Millisecond
+		want := time.Duration(rand.New(rand.NewSource(seed)).Int63n(int64(max)))
+		var first time.Duration
+		start := time.Now()
+		worker := func(ctx context.Context) (interface{}, error) {
+			first = time.Since(start)
+			return nil, nil
+		}
+		result := retry.Func(context.Background(), time.Millisecond, worker, retry.JitteredStart(max, rand.NewSource(seed)))
+		assert.NoError(t, result.Err)
+		assert.GreaterOrEqual(t, int64(first), int64(want))
+		assert.Less(t, int64(first), int64(max+50*time.Millisecond))
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		t.Log("Func should return without calling the worker function if the context ends during the delay.")
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			return nil, nil
+		}
+		start := time.Now()
+		result := retry.Func(ctx, time.Millisecond, worker, retry.JitteredStart(time.Hour, rand.NewSource(1)))
+		assert.Error(t, result.Err)
+		assert.Less(t, int64(time.Since(start)), int64(time.Second))
+		assert.Equal(t, 0, calls)
+	})
+}
+
+func TestJitteredStartShared(t *testing.T) {
+	t.Run("concurrent", func(t *testing.T) {
+		t.Log("The JitteredStart option should be safe to apply from many calls at the same time.")
+		opt := retry.JitteredStart(time.Millisecond, rand.NewSource(1))
+		worker := func(ctx context.Context) (interface{}, error) {
+			return nil, nil
+		}
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, retry.Func(context.Background(), time.Millisecond, worker, opt).Err)
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func TestFullJitter(t *testing.T) {
+	t.Run("range", func(t *testing.T) {
+		t.Log("FullJitter should wait between 0 and the capped exponential ceiling.")
+		base, max := 10*time.Millisecond, time.Second
+		b := retry.FullJitter(base, max, rand.NewSource(42))
+		for number := 1; number <= 100; number++ {
+			ceiling := max
+			if number <= 7 {
+				ceiling = base << uint(number-1)
+			}
+			d := b.Next(retry.Attempt{Number: number})
+			assert.GreaterOrEqual(t, int64(d), int64(0))
+			assert.LessOrEqual(t, int64(d), int64(ceiling))
+		}
+	})
+
+	t.Run("seeded", func(t *testing.T) {
+		t.Log("FullJitter should draw the same waits from the same seed.")
+		b1 := retry.FullJitter(time.Millisecond, time.Second, rand.NewSource(7))
+		b2 := retry.FullJitter(time.Millisecond, time.Second, rand.NewSource(7))
+		for number := 1; number <= 20; number++ {
+			a := retry.Attempt{Number: number}
+			assert.Equal(t, b1.Next(a), b2.Next(a))
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package retry
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// errorJSON is the JSON representation of an error.
+type errorJSON struct {
+	Error  string `json:"error"`
+	Since  string `json:"since,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	Cause  string `json:"cause,omitempty"`
+}
+
+// newErrorJSON returns the JSON representation of err, or nil if there is
+// no error. Only *Error has the since, reason and cause fields.
+func newErrorJSON(err error) *errorJSON {
+	if err == nil {
+		return nil
+	}
+	e, ok := err.(*Error)
+	if !ok {
+		return &errorJSON{Error: err.Error()}
+	}
+
+	ej := errorJSON{Error: e.Error(), Since: e.since.String()}
+	if e.reason != 0 {
+		ej.Reason = e.reason.String()
+	}
+	if e.errWork != nil {
+		ej.Cause = e.errWork.Error()
+	}
+	return &ej
+}
+
+// MarshalJSON implements the json.Marshaler interface. It emits the error
+// message with the time since the work started, the reason and the worker
+// function error that caused it.
+func (err *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newErrorJSON(err))
+}
+
+// MarshalJSON implements the json.Marshaler interface. It emits the value,
+// when the work started and finished, whether it is stale or stopped, and
+// the error message, plus the fields of *Error when the error is one. A
+// value that cannot be marshaled is emitted as its fmt representation.
+func (r Result) MarshalJSON() ([]byte, error) {
+	value, err := json.Marshal(r.Value)
+	if err != nil {
+		value, _ = json.Marshal(fmt.Sprintf("%v", r.Value))
+	}
+
+	return json.Marshal(struct {
+		Value      json.RawMessage `json:"value"`
+		StartedAt  *time.Time      `json:"started_at,omitempty"`
+		FinishedAt *time.Time      `json:"finished_at,omitempty"`
+		Stale      bool            `json:"stale,omitempty"`
+		Stopped    bool            `json:"stopped,omitempty"`
+		*errorJSON
+	}{value, timeJSON(r.StartedAt), timeJSON(r.FinishedAt), r.Stale, r.Stopped, newErrorJSON(r.Err)})
+}
+
+// timeJSON returns the time to marshal, or nil if it is zero so it is
+// omitted.
+func timeJSON(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
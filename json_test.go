@@ -0,0 +1,107 @@
+package retry_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultJSON(t *testing.T) {
+	type resultJSON struct {
+		Value  interface{} `json:"value"`
+		Error  string      `json:"error"`
+		Since  string      `json:"since"`
+		Reason string      `json:"reason"`
+		Cause  string      `json:"cause"`
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Log("Result should marshal the value without an error.")
+		data, err := json.Marshal(retry.Result{Value: map[string]int{"n": 1}})
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"value":{"n":1}}`, string(data))
+	})
+
+	t.Run("timestamps", func(t *testing.T) {
+		t.Log("Result should marshal when the work started and finished.")
+		started := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		finished := started.Add(1500 * time.Millisecond)
+		data, err := json.Marshal(retry.Result{Value: "ok", StartedAt: started, FinishedAt: finished})
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"value":"ok","started_at":"2020-01-02T03:04:05Z","finished_at":"2020-01-02T03:04:06.5Z"}`, string(data))
+	})
+
+	t.Run("worktimestamps", func(t *testing.T) {
+		t.Log("Result should marshal the timestamps set by Func.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return "ok", nil
+		}
+		data, err := json.Marshal(retry.Func(context.Background(), time.Millisecond, worker))
+		assert.NoError(t, err)
+		var r struct {
+			StartedAt  time.Time `json:"started_at"`
+			FinishedAt time.Time `json:"finished_at"`
+		}
+		assert.NoError(t, json.Unmarshal(data, &r))
+		assert.False(t, r.StartedAt.IsZero())
+		assert.False(t, r.FinishedAt.Before(r.StartedAt))
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		t.Log("Result should marshal that the value is stale.")
+		data, err := json.Marshal(retry.Result{Value: "cached", Stale: true})
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"value":"cached","stale":true}`, string(data))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Log("Result should marshal the plain error message.")
+		data, err := json.Marshal(retry.Result{Err: errors.New("some error")})
+		assert.NoError(t, err)
+		var r resultJSON
+		assert.NoError(t, json.Unmarshal(data, &r))
+		assert.Equal(t, resultJSON{Error: "some error"}, r)
+	})
+
+	t.Run("retryerror", func(t *testing.T) {
+		t.Log("Result should marshal the since duration and cause of *Error.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return nil, retry.Permanent(errors.New("bad request"))
+		}
+		result := retry.Func(context.Background(), time.Millisecond, worker)
+		data, err := json.Marshal(result)
+		assert.NoError(t, err)
+		var r resultJSON
+		assert.NoError(t, json.Unmarshal(data, &r))
+		assert.Nil(t, r.Value)
+		assert.Equal(t, result.Err.Error(), r.Error)
+		assert.Equal(t, "permanent", r.Reason)
+		assert.Equal(t, "bad request", r.Cause)
+		_, err = time.ParseDuration(r.Since)
+		assert.NoError(t, err)
+
+		var e *retry.Error
+		if assert.True(t, errors.As(result.Err, &e)) {
+			errData, err := json.Marshal(e)
+			assert.NoError(t, err)
+			var ej resultJSON
+			assert.NoError(t, json.Unmarshal(errData, &ej))
+			assert.Equal(t, resultJSON{Error: r.Error, Since: r.Since, Reason: r.Reason, Cause: r.Cause}, ej)
+		}
+	})
+
+	t.Run("unmarshalable", func(t *testing.T) {
+		t.Log("Result should fall back to the fmt representation of the value.")
+		data, err := json.Marshal(retry.Result{Value: make(chan int)})
+		assert.NoError(t, err)
+		var r resultJSON
+		assert.NoError(t, json.Unmarshal(data, &r))
+		assert.IsType(t, "", r.Value)
+		assert.Regexp(t, `^0x[0-9a-f]+$`, r.Value)
+	})
+}
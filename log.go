@@ -0,0 +1,55 @@
+package retry
+
+import "context"
+
+// Logger is the interface used to log worker function attempts, retries
+// and timeouts. It is satisfied by *log.Logger. Since worker functions run
+// in their own goroutines, the logger must be safe for concurrent use, and
+// it should return quickly, as it is called on every attempt.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// UseLogger sets the logger that receives a line for every attempt, retry
+// and timeout, with the worker name, attempt number and error. A nil logger
+// logs nothing.
+func UseLogger(l Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// log writes the event to the logger, if any. It is called synchronously
+// from the goroutine running the worker function, so a logger that locks on
+// every call, like *log.Logger, briefly serializes the worker functions.
+func (o *options) log(event string, name string, attempt int, err error) {
+	if o.logger == nil {
+		return
+	}
+	o.logger.Printf("event=%s worker=%q attempt=%d err=%v", event, name, attempt, err)
+}
+
+// loggerKey is the context key used to store the request-scoped logger.
+type loggerKey struct{}
+
+// discard is the Logger that logs nothing.
+type discard struct{}
+
+// Printf implements the Logger interface.
+func (discard) Printf(format string, v ...interface{}) {}
+
+// WithLogger returns a copy of the context carrying the logger, so the worker
+// functions receiving the context, or a context derived from it, can log
+// with it, see LoggerFromContext. It does not set the logger of UseLogger.
+func WithLogger(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// LoggerFromContext returns the logger set by WithLogger, or a logger that
+// logs nothing if there is none.
+func LoggerFromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(loggerKey{}).(Logger); ok && l != nil {
+		return l
+	}
+	return discard{}
+}
@@ -0,0 +1,90 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+// logger captures the logged lines.
+type logger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *logger) Printf(format string, v ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func TestUseLogger(t *testing.T) {
+	t.Run("all", func(t *testing.T) {
+		t.Log("All should log every attempt, retry and timeout.")
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+		var calls int
+		worker1 := func(ctx context.Context) (interface{}, error) {
+			calls++
+			if calls == 1 {
+				return nil, errors.New("first")
+			}
+			return "ok", nil
+		}
+		worker2 := func(ctx context.Context) (interface{}, error) {
+			<-ctx.Done()
+			return nil, errors.New("never")
+		}
+		workers := map[string]retry.Worker{"worker1": worker1, "worker2": worker2}
+		var l logger
+		retry.All(ctx, time.Millisecond, workers, retry.MaxGoroutines, retry.UseLogger(&l))
+		assert.Contains(t, l.lines, `event=attempt worker="worker1" attempt=1 err=first`)
+		assert.Contains(t, l.lines, `event=retry worker="worker1" attempt=1 err=first`)
+		assert.Contains(t, l.lines, `event=attempt worker="worker1" attempt=2 err=<nil>`)
+		assert.Contains(t, l.lines, `event=attempt worker="worker2" attempt=1 err=never`)
+		assert.Contains(t, l.lines, `event=timeout worker="worker2" attempt=1 err=never`)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		t.Log("Func should not log with a nil logger.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return nil, nil
+		}
+		result := retry.Func(context.Background(), time.Millisecond, worker, retry.UseLogger(nil))
+		assert.NoError(t, result.Err)
+	})
+}
+
+func TestLoggerFromContext(t *testing.T) {
+	t.Run("first", func(t *testing.T) {
+		t.Log("The worker functions should log with the logger of the context.")
+		var l logger
+		worker := func(name string) retry.Worker {
+			return func(ctx context.Context) (interface{}, error) {
+				retry.LoggerFromContext(ctx).Printf("worker %s", name)
+				return name, nil
+			}
+		}
+		ctx := retry.WithLogger(context.Background(), &l)
+		result := retry.Func(ctx, time.Millisecond, worker("func"))
+		assert.NoError(t, result.Err)
+		results := retry.All(ctx, time.Millisecond, map[string]retry.Worker{"all": worker("all")}, retry.MaxGoroutines)
+		assert.NoError(t, results["all"].Err)
+		result = retry.First(ctx, time.Millisecond, map[string]retry.Worker{"first": worker("first")}, retry.MaxGoroutines)
+		assert.NoError(t, result.Err)
+		assert.Equal(t, []string{"worker func", "worker all", "worker first"}, l.lines)
+	})
+
+	t.Run("none", func(t *testing.T) {
+		t.Log("LoggerFromContext should return a logger that logs nothing without one.")
+		l := retry.LoggerFromContext(context.Background())
+		assert.NotNil(t, l)
+		l.Printf("nothing %d", 1)
+	})
+}
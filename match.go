@@ -0,0 +1,29 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNoMatch is the error of a worker function whose value did not pass the
+// match of FirstMatch.
+var ErrNoMatch = errors.New("worker function value did not match")
+
+// FirstMatch is like First, but returns the first result whose value passes
+// match. A worker function returning a value that does not pass match fails
+// with ErrNoMatch and is retried.
+func FirstMatch(ctx context.Context, retryInterval time.Duration, workers map[string]Worker, maxGs int, match func(value interface{}) bool, opts ...Option) Result {
+	matching := make(map[string]Worker, len(workers))
+	for name, worker := range workers {
+		worker := worker
+		matching[name] = func(ctx context.Context) (interface{}, error) {
+			value, err := worker(ctx)
+			if err == nil && !match(value) {
+				return nil, ErrNoMatch
+			}
+			return value, err
+		}
+	}
+	return First(ctx, retryInterval, matching, maxGs, opts...)
+}
@@ -0,0 +1,55 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFirstMatch(t *testing.T) {
+	sleeper := func(d time.Duration, value int) retry.Worker {
+		return func(ctx context.Context) (interface{}, error) {
+			select {
+			case <-time.After(d):
+				return value, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+	even := func(value interface{}) bool {
+		return value.(int)%2 == 0
+	}
+
+	t.Run("match", func(t *testing.T) {
+		t.Log("FirstMatch should return the first value passing the match.")
+		workers := map[string]retry.Worker{
+			"fastest": sleeper(time.Millisecond, 1),
+			"second":  sleeper(10*time.Millisecond, 2),
+			"slowest": sleeper(time.Second, 4),
+		}
+		result := retry.FirstMatch(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, even)
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, 2, result.Value)
+		}
+	})
+
+	t.Run("nomatch", func(t *testing.T) {
+		t.Log("FirstMatch should retry the values not passing the match until the context ends.")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		odd := func(ctx context.Context) (interface{}, error) {
+			return 1, nil
+		}
+		workers := map[string]retry.Worker{"odd": odd}
+		result := retry.FirstMatch(ctx, time.Millisecond, workers, retry.MaxGoroutines, even)
+		var err *retry.Error
+		if assert.True(t, errors.As(result.Err, &err)) {
+			assert.True(t, errors.Is(err.Errors()["odd"], retry.ErrNoMatch))
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Middleware wraps a worker function, adding behavior around its calls.
+type Middleware func(Worker) Worker
+
+// Chain composes the middlewares into one. The first middleware is the
+// outermost, so its code runs first before the worker function call and
+// last after it.
+func Chain(mw ...Middleware) Middleware {
+	return func(worker Worker) Worker {
+		for i := len(mw) - 1; i >= 0; i-- {
+			worker = mw[i](worker)
+		}
+		return worker
+	}
+}
+
+// WithTimeout bounds each call to the worker function with its own timeout.
+// A call exceeding it fails with the context error and is retried.
+func WithTimeout(d time.Duration) Middleware {
+	return func(worker Worker) Worker {
+		return func(ctx context.Context) (interface{}, error) {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+			return worker(ctx)
+		}
+	}
+}
+
+// WithLogging logs every call to the worker function, with the attempt
+// number, how long it took and the error it returned.
+func WithLogging(l Logger) Middleware {
+	return func(worker Worker) Worker {
+		return func(ctx context.Context) (interface{}, error) {
+			start := time.Now()
+			value, err := worker(ctx)
+			l.Printf("event=call attempt=%d duration=%v err=%v", AttemptFromContext(ctx), time.Since(start), err)
+			return value, err
+		}
+	}
+}
+
+// WithRecover turns a panic in the worker function into an error, so it is
+// retried like any other failure.
+func WithRecover() Middleware {
+	return func(worker Worker) Worker {
+		return func(ctx context.Context) (value interface{}, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					value, err = nil, fmt.Errorf("worker function panic: %v", r)
+				}
+			}()
+			return worker(ctx)
+		}
+	}
+}
+
+// Map returns a worker function that applies transform to the value of
+// each successful call to the worker function. A transform error fails the
+// call like a worker function error, so it is retried unless it is
+// permanent.
+func Map(worker Worker, transform func(value interface{}) (interface{}, error)) Worker {
+	return func(ctx context.Context) (interface{}, error) {
+		value, err := worker(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return transform(value)
+	}
+}
@@ -0,0 +1,133 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChain(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		t.Log("Chain should run the first middleware as the outermost.")
+		var calls []string
+		trace := func(name string) retry.Middleware {
+			return func(worker retry.Worker) retry.Worker {
+				return func(ctx context.Context) (interface{}, error) {
+					calls = append(calls, name+" before")
+					value, err := worker(ctx)
+					calls = append(calls, name+" after")
+					return value, err
+				}
+			}
+		}
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls = append(calls, "worker")
+			return "ok", nil
+		}
+		result := retry.Func(context.Background(), time.Millisecond, retry.Chain(trace("a"), trace("b"))(worker))
+		assert.NoError(t, result.Err)
+		assert.Equal(t, []string{"a before", "b before", "worker", "b after", "a after"}, calls)
+	})
+}
+
+func TestWithTimeout(t *testing.T) {
+	t.Run("retry", func(t *testing.T) {
+		t.Log("WithTimeout should fail a slow call so it is retried.")
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			if calls == 1 {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return "ok", nil
+		}
+		result := retry.Func(context.Background(), time.Millisecond, retry.WithTimeout(time.Millisecond)(worker))
+		assert.NoError(t, result.Err)
+		assert.Equal(t, 2, calls)
+	})
+}
+
+func TestWithLogging(t *testing.T) {
+	t.Run("log", func(t *testing.T) {
+		t.Log("WithLogging should log every call with the attempt number.")
+		var l logger
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			if calls == 1 {
+				return nil, errors.New("first")
+			}
+			return "ok", nil
+		}
+		result := retry.Func(context.Background(), time.Millisecond, retry.WithLogging(&l)(worker))
+		assert.NoError(t, result.Err)
+		if assert.Len(t, l.lines, 2) {
+			assert.Regexp(t, `^event=call attempt=1 duration=.+ err=first$`, l.lines[0])
+			assert.Regexp(t, `^event=call attempt=2 duration=.+ err=<nil>$`, l.lines[1])
+		}
+	})
+}
+
+func TestWithRecover(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		t.Log("WithRecover should turn a panic into a retried error.")
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			if calls == 1 {
+				panic("boom")
+			}
+			return "ok", nil
+		}
+		mw := retry.Chain(retry.WithRecover(), retry.WithTimeout(time.Second))
+		_, err := mw(worker)(context.Background())
+		assert.EqualError(t, err, "worker function panic: boom")
+		result := retry.Func(context.Background(), time.Millisecond, mw(worker))
+		assert.NoError(t, result.Err)
+		assert.Equal(t, 2, calls)
+	})
+}
+
+func TestMap(t *testing.T) {
+	t.Run("retry", func(t *testing.T) {
+		t.Log("Map should transform the value and retry the worker function when the transform fails.")
+		var calls, transforms int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			return "42", nil
+		}
+		transform := func(value interface{}) (interface{}, error) {
+			transforms++
+			if transforms == 1 {
+				return nil, errors.New("not parsed")
+			}
+			return len(value.(string)), nil
+		}
+		result := retry.Func(context.Background(), time.Millisecond, retry.Map(worker, transform))
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, 2, result.Value)
+		}
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, 2, transforms)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Log("Map should not transform the value when the worker function fails.")
+		errWork := errors.New("down")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return "ignored", errWork
+		}
+		transform := func(value interface{}) (interface{}, error) {
+			t.Error("transform called")
+			return value, nil
+		}
+		value, err := retry.Map(worker, transform)(context.Background())
+		assert.Nil(t, value)
+		assert.Equal(t, errWork, err)
+	})
+}
@@ -0,0 +1,52 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MultiError holds the errors of the failed worker functions of AllErr.
+type MultiError struct {
+	errs map[string]error
+}
+
+// Error implements the error interface and returns the errors of the failed
+// worker functions, sorted by name. The errors start with the name.
+func (err *MultiError) Error() string {
+	names := make([]string, 0, len(err.errs))
+	for name := range err.errs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	msgs := make([]string, len(names))
+	for i, name := range names {
+		msgs[i] = err.errs[name].Error()
+	}
+	return fmt.Sprintf("%d worker functions failed : %s", len(names), strings.Join(msgs, "; "))
+}
+
+// Errors returns the error of each failed worker function, keyed by name.
+func (err *MultiError) Errors() map[string]error {
+	return err.errs
+}
+
+// AllErr is like All but also returns a *MultiError with the failed worker
+// functions, or nil if all of them succeeded.
+func AllErr(ctx context.Context, retryInterval time.Duration, workers map[string]Worker, maxGs int, opts ...Option) (map[string]Result, error) {
+	results := All(ctx, retryInterval, workers, maxGs, opts...)
+
+	errs := make(map[string]error)
+	for name, result := range results {
+		if result.Failed() {
+			errs[name] = result.Err
+		}
+	}
+	if len(errs) == 0 {
+		return results, nil
+	}
+	return results, &MultiError{errs: errs}
+}
@@ -0,0 +1,43 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllErr(t *testing.T) {
+	success := func(ctx context.Context) (interface{}, error) {
+		return "ok", nil
+	}
+	fail := func(ctx context.Context) (interface{}, error) {
+		return nil, retry.Permanent(errors.New("down"))
+	}
+
+	t.Run("noerror", func(t *testing.T) {
+		t.Log("AllErr should return no error when all worker functions succeed.")
+		workers := map[string]retry.Worker{"a": success, "b": success}
+		results, err := retry.AllErr(context.Background(), time.Millisecond, workers, retry.MaxGoroutines)
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		t.Log("AllErr should return a MultiError with all the failed worker functions.")
+		workers := map[string]retry.Worker{"a": success, "b": fail, "c": fail}
+		results, err := retry.AllErr(context.Background(), time.Millisecond, workers, retry.MaxGoroutines)
+		assert.Len(t, results, 3)
+		var merr *retry.MultiError
+		if assert.True(t, errors.As(err, &merr)) {
+			errs := merr.Errors()
+			assert.Len(t, errs, 2)
+			assert.Equal(t, results["b"].Err, errs["b"])
+			assert.Equal(t, results["c"].Err, errs["c"])
+			assert.Regexp(t, `^2 worker functions failed : worker "b" retry stopped after .+ : down; worker "c" retry stopped after .+ : down$`, err.Error())
+		}
+	})
+}
@@ -0,0 +1,18 @@
+package retry
+
+import "time"
+
+// Observer is notified about the worker function attempts. Since worker
+// functions run in their own goroutines, it must be safe for concurrent use.
+type Observer interface {
+	// AttemptCompleted is called after every call to a worker function,
+	// with how long the call took and the error it returned.
+	AttemptCompleted(name string, attempt int, d time.Duration, err error)
+}
+
+// UseObserver sets the observer notified about the worker function attempts.
+func UseObserver(obs Observer) Option {
+	return func(o *options) {
+		o.observer = obs
+	}
+}
@@ -0,0 +1,85 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+// attempt is an attempt recorded by the observer.
+type attempt struct {
+	name    string
+	attempt int
+	d       time.Duration
+	err     error
+}
+
+// observer records the attempts.
+type observer struct {
+	mu       sync.Mutex
+	attempts []attempt
+}
+
+func (o *observer) AttemptCompleted(name string, n int, d time.Duration, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.attempts = append(o.attempts, attempt{name: name, attempt: n, d: d, err: err})
+}
+
+// fakeClock advances by step every time it is read.
+type fakeClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(c.step)
+	return c.now
+}
+
+func TestUseObserver(t *testing.T) {
+	t.Run("attempts", func(t *testing.T) {
+		t.Log("Func should report the duration of each attempt using the clock.")
+		errWork := errors.New("not yet")
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			if calls < 3 {
+				return nil, errWork
+			}
+			return nil, nil
+		}
+		var obs observer
+		clock := fakeClock{step: time.Millisecond}
+		result := retry.Func(context.Background(), time.Nanosecond, worker, retry.UseObserver(&obs), retry.UseClock(clock.Now))
+		assert.NoError(t, result.Err)
+		assert.Equal(t, []attempt{
+			{attempt: 1, d: time.Millisecond, err: errWork},
+			{attempt: 2, d: time.Millisecond, err: errWork},
+			{attempt: 3, d: time.Millisecond},
+		}, obs.attempts)
+	})
+
+	t.Run("all", func(t *testing.T) {
+		t.Log("All should report the attempts with the worker function names.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return nil, nil
+		}
+		workers := map[string]retry.Worker{"worker1": worker, "worker2": worker}
+		var obs observer
+		retry.All(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, retry.UseObserver(&obs))
+		names := make(map[string]int)
+		for _, a := range obs.attempts {
+			names[a.name] = a.attempt
+		}
+		assert.Equal(t, map[string]int{"worker1": 1, "worker2": 1}, names)
+	})
+}
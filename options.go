@@ -0,0 +1,223 @@
+package retry
+
+import (
+	"errors"
+	"time"
+)
+
+// Option configures how the worker functions are retried. Options that only
+// apply to some of the functions say so in their documentation.
+type Option func(*options)
+
+// options holds the configuration set by the options.
+type options struct {
+	logger      Logger
+	key         func(name string) string
+	onTimeout   func(lastErr error)
+	backoff     Backoff
+	maxAttempts int
+	retryIf     func(err error) bool
+	jitter      *jitter
+	slots       chan struct{}
+	failFast    bool
+	observer    Observer
+	clock       func() time.Time
+	grace       time.Duration
+	nonNil      bool
+	maxErrors   int
+	scheduler   Scheduler
+	hardTimeout bool
+	gauge       *Gauge
+	stagger     *int64
+	once        bool
+	doneWhen    func(value interface{}, err error) bool
+	controller  *Controller
+	minLatency  time.Duration
+	keepLast    bool
+	onFinished  func(total time.Duration)
+	turns       chan struct{}
+	keepLosers  bool
+	fallback    interface{}
+	useFallback bool
+	extend      *extend
+
+	continueOnFailure bool
+	proportional      int
+}
+
+// newOptions applies the options over the default configuration.
+func newOptions(opts []Option) *options {
+	o := options{clock: time.Now}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &o
+}
+
+// OnTimeout sets a function called once when the context ends before the
+// worker function succeeds, with the last worker function error. It runs on
+// the goroutine retrying the worker function, before the result is returned.
+// With All and First it is called for each worker function that times out,
+// but not for the ones First cancels because another worker function won.
+func OnTimeout(f func(lastErr error)) Option {
+	return func(o *options) {
+		o.onTimeout = f
+	}
+}
+
+// OnFinished sets a function called when Func, All, First or FirstN returns,
+// with how long the whole call took, measured with the clock set by
+// UseClock. It runs on the calling goroutine, before the call returns.
+func OnFinished(f func(total time.Duration)) Option {
+	return func(o *options) {
+		o.onFinished = f
+	}
+}
+
+// finished calls the OnFinished function, if any, with the time since start.
+func (o *options) finished(start time.Time) {
+	if o.onFinished != nil {
+		o.onFinished(o.now().Sub(start))
+	}
+}
+
+// MaxAttempts stops retrying the worker function after n failed attempts,
+// even if the context has not ended. Zero, the default, means no limit.
+func MaxAttempts(n int) Option {
+	return func(o *options) {
+		o.maxAttempts = n
+	}
+}
+
+// MaxErrors bounds how many worker function errors First and FirstN keep in
+// the Errors of their error, for calls with many worker functions. The
+// errors over the limit are only counted, and the error message says how
+// many more there were. Zero, the default, means no limit.
+func MaxErrors(n int) Option {
+	return func(o *options) {
+		o.maxErrors = n
+	}
+}
+
+// FailFast makes All cancel the remaining worker functions as soon as one of
+// them fails with an error that must not be retried, see RetryIf. All still
+// waits for the cancelled worker functions to return, their results having
+// the ReasonFailFast reason. It affects All, the calls built on it, like
+// AllAsync, AllErr, AllOnce, AllByCompletion and Repeat, and Group.
+func FailFast() Option {
+	return func(o *options) {
+		o.failFast = true
+	}
+}
+
+// LoserGrace makes First wait up to d, after a worker function wins, for the
+// cancelled worker functions to return, so their cleanup completes before
+// First does. Worker functions still running after d are left behind. It only
+// affects First and FirstN.
+func LoserGrace(d time.Duration) Option {
+	return func(o *options) {
+		o.grace = d
+	}
+}
+
+// ErrNilValue is the error of a worker function that returned neither a
+// value nor an error, when RequireNonNilValue is set.
+var ErrNilValue = errors.New("worker function returned a nil value")
+
+// RequireNonNilValue makes a worker function that returns a nil value and no
+// error fail with ErrNilValue, so it is retried as not ready yet.
+func RequireNonNilValue() Option {
+	return func(o *options) {
+		o.nonNil = true
+	}
+}
+
+// HardTimeout makes the worker functions run in their own goroutine, so the
+// function returns as soon as the context ends even if a worker function
+// ignores the cancellation. The worker function call is abandoned, and its
+// goroutine leaks until the call eventually returns.
+func HardTimeout() Option {
+	return func(o *options) {
+		o.hardTimeout = true
+	}
+}
+
+// WarmStart decides whether the first attempts of all the worker functions
+// run at once, the default, or are staggered by the retry interval to spread
+// the initial load: the n-th worker function to start waits n-1 retry
+// intervals before its first attempt.
+func WarmStart(warm bool) Option {
+	return func(o *options) {
+		o.stagger = nil
+		if !warm {
+			o.stagger = new(int64)
+		}
+	}
+}
+
+// KeepLastValue makes the result of a worker function that times out carry
+// the most recent non-nil value it returned, along with the error.
+func KeepLastValue() Option {
+	return func(o *options) {
+		o.keepLast = true
+	}
+}
+
+// FallbackValue makes a worker function that times out return the
+// fallback, such as a last known good value, instead of an error. The
+// result has a nil Err and Stale set, so callers must check Stale to tell
+// it from a fresh value. It applies only when the caller's context ends,
+// not to worker functions cancelled internally, like First losers.
+func FallbackValue(fallback interface{}) Option {
+	return func(o *options) {
+		o.fallback = fallback
+		o.useFallback = true
+	}
+}
+
+// DoneWhen sets the predicate that decides when the worker function is done,
+// instead of it returning no error. The worker function is retried while
+// done returns false, even without an error, and the value and error of the
+// call that made done return true are the result. If the context ends first,
+// the result has the value of the last call along with the error.
+func DoneWhen(done func(value interface{}, err error) bool) Option {
+	return func(o *options) {
+		o.doneWhen = done
+	}
+}
+
+// NoCancelLosers makes First leave the losing worker functions running after
+// it returns, for example to warm caches, bound only to the context passed
+// to First. Their goroutines keep retrying until they succeed, stop being
+// retried or the context ends, so the context should end at some point.
+// Their results are discarded. It only affects First.
+func NoCancelLosers() Option {
+	return func(o *options) {
+		o.keepLosers = true
+	}
+}
+
+// FirstMinLatency makes First hold back the successes returned sooner than
+// floor after it started, as likely stale, instead of returning them right
+// away. Once the floor has passed, or all the worker functions completed,
+// First returns the last of them, the slowest one, without waiting for the
+// other worker functions. The time is measured with the clock set by
+// UseClock. It only affects First.
+func FirstMinLatency(floor time.Duration) Option {
+	return func(o *options) {
+		o.minLatency = floor
+	}
+}
+
+// UseClock sets the function used to read the current time when measuring
+// the worker functions. It defaults to time.Now.
+func UseClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.clock = now
+	}
+}
+
+// now returns the current time of the clock.
+func (o *options) now() time.Time {
+	return o.clock()
+}
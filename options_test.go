@@ -0,0 +1,560 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnTimeout(t *testing.T) {
+	t.Run("func", func(t *testing.T) {
+		t.Log("OnTimeout should be called once with the last worker function error before Func returns.")
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			return nil, errors.New("last")
+		}
+		var lastErrs []error
+		onTimeout := func(lastErr error) {
+			lastErrs = append(lastErrs, lastErr)
+		}
+		result := retry.Func(ctx, time.Millisecond, worker, retry.OnTimeout(onTimeout))
+		assert.Error(t, result.Err)
+		if assert.Len(t, lastErrs, 1) {
+			assert.EqualError(t, lastErrs[0], "last")
+		}
+	})
+
+	t.Run("noerror", func(t *testing.T) {
+		t.Log("OnTimeout should not be called when the worker function succeeds.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return nil, nil
+		}
+		onTimeout := func(lastErr error) {
+			t.Error("OnTimeout should not be called")
+		}
+		result := retry.Func(context.Background(), time.Millisecond, worker, retry.OnTimeout(onTimeout))
+		assert.NoError(t, result.Err)
+	})
+
+	t.Run("all", func(t *testing.T) {
+		t.Log("OnTimeout should be called for each worker function that times out in All.")
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+		worker1 := func(ctx context.Context) (interface{}, error) {
+			return nil, nil
+		}
+		worker2 := func(ctx context.Context) (interface{}, error) {
+			return nil, errors.New("worker2")
+		}
+		workers := map[string]retry.Worker{"worker1": worker1, "worker2": worker2}
+		var mu sync.Mutex
+		var lastErrs []error
+		onTimeout := func(lastErr error) {
+			mu.Lock()
+			defer mu.Unlock()
+			lastErrs = append(lastErrs, lastErr)
+		}
+		retry.All(ctx, time.Millisecond, workers, retry.MaxGoroutines, retry.OnTimeout(onTimeout))
+		if assert.Len(t, lastErrs, 1) {
+			assert.EqualError(t, lastErrs[0], "worker2")
+		}
+	})
+
+	t.Run("first", func(t *testing.T) {
+		t.Log("OnTimeout should not be called for worker functions cancelled because another one won.")
+		started := make(chan struct{})
+		winner := func(ctx context.Context) (interface{}, error) {
+			<-started
+			return "ok", nil
+		}
+		cancelled := make(chan struct{})
+		loser := func(ctx context.Context) (interface{}, error) {
+			close(started)
+			<-ctx.Done()
+			defer close(cancelled)
+			return nil, errors.New("loser")
+		}
+		workers := map[string]retry.Worker{"winner": winner, "loser": loser}
+		var calls int32
+		onTimeout := func(lastErr error) {
+			atomic.AddInt32(&calls, 1)
+		}
+		result := retry.First(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, retry.OnTimeout(onTimeout))
+		assert.NoError(t, result.Err)
+		<-cancelled
+		time.Sleep(5 * time.Millisecond)
+		assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+	})
+}
+
+func TestFailFast(t *testing.T) {
+	t.Run("cancel", func(t *testing.T) {
+		t.Log("All should cancel the remaining worker functions after a permanent failure.")
+		errBad := errors.New("bad request")
+		bad := func(ctx context.Context) (interface{}, error) {
+			return nil, retry.Permanent(errBad)
+		}
+		pending := func(ctx context.Context) (interface{}, error) {
+			return nil, errors.New("not yet")
+		}
+		workers := map[string]retry.Worker{"bad": bad, "pending1": pending, "pending2": pending}
+		results := retry.All(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, retry.FailFast())
+		assert.Len(t, results, 3)
+		var err *retry.Error
+		if assert.True(t, errors.As(results["bad"].Err, &err)) {
+			assert.Equal(t, retry.ReasonPermanent, err.Reason())
+			assert.True(t, errors.Is(err, errBad))
+		}
+		for _, name := range []string{"pending1", "pending2"} {
+			if assert.True(t, errors.As(results[name].Err, &err)) {
+				assert.Equal(t, retry.ReasonFailFast, err.Reason())
+			}
+		}
+	})
+}
+
+func TestLoserGrace(t *testing.T) {
+	t.Run("cleanup", func(t *testing.T) {
+		t.Log("First should wait for the cancelled worker functions to clean up.")
+		started := make(chan struct{})
+		var cleaned int32
+		winner := func(ctx context.Context) (interface{}, error) {
+			<-started
+			return "winner", nil
+		}
+		loser := func(ctx context.Context) (interface{}, error) {
+			close(started)
+			<-ctx.Done()
+			time.Sleep(10 * time.Millisecond)
+			atomic.StoreInt32(&cleaned, 1)
+			return nil, ctx.Err()
+		}
+		workers := map[string]retry.Worker{"winner": winner, "loser": loser}
+		result := retry.First(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, retry.LoserGrace(time.Second))
+		assert.NoError(t, result.Err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&cleaned))
+	})
+
+	t.Run("capped", func(t *testing.T) {
+		t.Log("First should not wait longer than the grace period.")
+		started := make(chan struct{})
+		block := make(chan struct{})
+		defer close(block)
+		winner := func(ctx context.Context) (interface{}, error) {
+			<-started
+			return "winner", nil
+		}
+		stuck := func(ctx context.Context) (interface{}, error) {
+			close(started)
+			<-block
+			return nil, errors.New("stuck")
+		}
+		workers := map[string]retry.Worker{"winner": winner, "stuck": stuck}
+		start := time.Now()
+		result := retry.First(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, retry.LoserGrace(20*time.Millisecond))
+		assert.NoError(t, result.Err)
+		assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	})
+}
+
+func TestRequireNonNilValue(t *testing.T) {
+	t.Run("retry", func(t *testing.T) {
+		t.Log("Func should retry a worker function returning a nil value.")
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			if calls <= 2 {
+				return nil, nil
+			}
+			return "ready", nil
+		}
+		result := retry.Func(context.Background(), time.Millisecond, worker, retry.RequireNonNilValue())
+		assert.NoError(t, result.Err)
+		assert.Equal(t, "ready", result.Value)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Log("Func should return ErrNilValue when the value never becomes non-nil.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return nil, nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		result := retry.Func(ctx, time.Millisecond, worker, retry.RequireNonNilValue())
+		var err *retry.Error
+		if assert.True(t, errors.As(result.Err, &err)) {
+			assert.True(t, errors.Is(err, retry.ErrNilValue))
+			assert.Equal(t, retry.ReasonTimeout, err.Reason())
+		}
+	})
+}
+
+func TestMaxErrors(t *testing.T) {
+	t.Run("cap", func(t *testing.T) {
+		t.Log("First should keep at most the maximum number of errors.")
+		fail := func(ctx context.Context) (interface{}, error) {
+			return nil, retry.Permanent(errors.New("down"))
+		}
+		workers := make(map[string]retry.Worker)
+		for i := 0; i < 1000; i++ {
+			workers[fmt.Sprint("worker", i)] = fail
+		}
+		result := retry.First(context.Background(), time.Millisecond, workers, 10, retry.MaxErrors(5))
+		var err *retry.Error
+		if assert.True(t, errors.As(result.Err, &err)) {
+			assert.Len(t, err.Errors(), 5)
+			assert.Contains(t, err.Error(), "all worker functions failed (5 errors kept, and 995 more)")
+		}
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		t.Log("First should keep all the errors by default.")
+		fail := func(ctx context.Context) (interface{}, error) {
+			return nil, retry.Permanent(errors.New("down"))
+		}
+		workers := make(map[string]retry.Worker)
+		for i := 0; i < 100; i++ {
+			workers[fmt.Sprint("worker", i)] = fail
+		}
+		result := retry.First(context.Background(), time.Millisecond, workers, retry.MaxGoroutines)
+		var err *retry.Error
+		if assert.True(t, errors.As(result.Err, &err)) {
+			assert.Len(t, err.Errors(), 100)
+			assert.NotContains(t, err.Error(), "more")
+		}
+	})
+}
+
+func TestHardTimeout(t *testing.T) {
+	t.Run("abandon", func(t *testing.T) {
+		t.Log("Func should return promptly when the worker function ignores the context.")
+		block := make(chan struct{})
+		defer close(block)
+		worker := func(ctx context.Context) (interface{}, error) {
+			<-block
+			return "late", nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		result := retry.Func(ctx, time.Millisecond, worker, retry.HardTimeout())
+		assert.Less(t, int64(time.Since(start)), int64(time.Second))
+		var err *retry.Error
+		if assert.True(t, errors.As(result.Err, &err)) {
+			assert.Equal(t, retry.ReasonTimeout, err.Reason())
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Log("Func should return the worker function result when it returns in time.")
+		var calls int32
+		worker := func(ctx context.Context) (interface{}, error) {
+			if atomic.AddInt32(&calls, 1) < 2 {
+				return nil, errors.New("not yet")
+			}
+			return "done", nil
+		}
+		result := retry.Func(context.Background(), time.Millisecond, worker, retry.HardTimeout())
+		assert.NoError(t, result.Err)
+		assert.Equal(t, "done", result.Value)
+	})
+}
+
+func TestWarmStart(t *testing.T) {
+	firsts := func(opts ...retry.Option) []time.Duration {
+		var mu sync.Mutex
+		var firsts []time.Duration
+		start := time.Now()
+		worker := func(ctx context.Context) (interface{}, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			firsts = append(firsts, time.Since(start))
+			return "ok", nil
+		}
+		workers := map[string]retry.Worker{"a": worker, "b": worker, "c": worker}
+		retry.All(context.Background(), 20*time.Millisecond, workers, retry.MaxGoroutines, opts...)
+		return firsts
+	}
+
+	t.Run("warm", func(t *testing.T) {
+		t.Log("All should run all the first attempts at once by default.")
+		for _, first := range firsts(retry.WarmStart(true)) {
+			assert.Less(t, int64(first), int64(20*time.Millisecond))
+		}
+	})
+
+	t.Run("staggered", func(t *testing.T) {
+		t.Log("All should stagger the first attempts by the retry interval.")
+		got := firsts(retry.WarmStart(false))
+		if assert.Len(t, got, 3) {
+			assert.Less(t, int64(got[0]), int64(20*time.Millisecond))
+			assert.GreaterOrEqual(t, int64(got[1]), int64(20*time.Millisecond))
+			assert.GreaterOrEqual(t, int64(got[2]), int64(40*time.Millisecond))
+		}
+	})
+}
+
+func TestDoneWhen(t *testing.T) {
+	ready := func(value interface{}, err error) bool {
+		return err == nil && value == "ready"
+	}
+
+	t.Run("done", func(t *testing.T) {
+		t.Log("Func should retry until the worker function result is done.")
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			if calls < 3 {
+				return "pending", nil
+			}
+			return "ready", nil
+		}
+		result := retry.Func(context.Background(), time.Millisecond, worker, retry.DoneWhen(ready))
+		assert.NoError(t, result.Err)
+		assert.Equal(t, "ready", result.Value)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Log("Func should return the last value and error when the context ends.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return "pending", nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		result := retry.Func(ctx, time.Millisecond, worker, retry.DoneWhen(ready))
+		assert.Equal(t, "pending", result.Value)
+		var err *retry.Error
+		if assert.True(t, errors.As(result.Err, &err)) {
+			assert.Equal(t, retry.ReasonTimeout, err.Reason())
+		}
+	})
+}
+
+func TestFirstMinLatency(t *testing.T) {
+	sleeper := func(d time.Duration, value string) retry.Worker {
+		return func(ctx context.Context) (interface{}, error) {
+			select {
+			case <-time.After(d):
+				return value, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	t.Run("skip", func(t *testing.T) {
+		t.Log("First should skip the success that is too fast, and wait for the floor.")
+		workers := map[string]retry.Worker{
+			"cached": sleeper(0, "cached"),
+			"fresh":  sleeper(5*time.Millisecond, "fresh"),
+			"slow":   sleeper(time.Second, "slow"),
+		}
+		start := time.Now()
+		result := retry.First(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, retry.FirstMinLatency(20*time.Millisecond))
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, "fresh", result.Value)
+		}
+		assert.GreaterOrEqual(t, int64(time.Since(start)), int64(20*time.Millisecond))
+		assert.Less(t, int64(time.Since(start)), int64(500*time.Millisecond))
+	})
+
+	t.Run("relax", func(t *testing.T) {
+		t.Log("First should return the slowest success when all are too fast.")
+		workers := map[string]retry.Worker{
+			"fastest": sleeper(0, "fastest"),
+			"fast":    sleeper(10*time.Millisecond, "fast"),
+		}
+		result := retry.First(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, retry.FirstMinLatency(time.Second))
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, "fast", result.Value)
+		}
+	})
+
+	t.Run("floor", func(t *testing.T) {
+		t.Log("First should return the discarded success once the floor passed, without waiting for the context.")
+		fail := func(ctx context.Context) (interface{}, error) {
+			return nil, errors.New("down")
+		}
+		workers := map[string]retry.Worker{"fast": sleeper(0, "fast"), "fail": fail}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		start := time.Now()
+		result := retry.First(ctx, time.Millisecond, workers, retry.MaxGoroutines, retry.FirstMinLatency(10*time.Millisecond))
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, "fast", result.Value)
+		}
+		assert.GreaterOrEqual(t, int64(time.Since(start)), int64(10*time.Millisecond))
+		assert.Less(t, int64(time.Since(start)), int64(500*time.Millisecond))
+	})
+
+	t.Run("failedlast", func(t *testing.T) {
+		t.Log("First should return the discarded success even when a failure arrives after it.")
+		fail := func(ctx context.Context) (interface{}, error) {
+			time.Sleep(10 * time.Millisecond)
+			return nil, retry.Permanent(errors.New("down"))
+		}
+		workers := map[string]retry.Worker{"fast": sleeper(0, "fast"), "fail": fail}
+		result := retry.First(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, retry.FirstMinLatency(time.Second))
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, "fast", result.Value)
+		}
+	})
+}
+
+func TestFallbackValue(t *testing.T) {
+	t.Run("timeout", func(t *testing.T) {
+		t.Log("Func should return the fallback as a stale value without an error on timeout.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return nil, errors.New("unavailable")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		result := retry.Func(ctx, time.Millisecond, worker, retry.FallbackValue("cached"))
+		assert.NoError(t, result.Err)
+		assert.Equal(t, "cached", result.Value)
+		assert.True(t, result.Stale)
+	})
+
+	t.Run("fresh", func(t *testing.T) {
+		t.Log("Func should return the worker function value when it succeeds in time.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return "fresh", nil
+		}
+		result := retry.Func(context.Background(), time.Millisecond, worker, retry.FallbackValue("cached"))
+		assert.NoError(t, result.Err)
+		assert.Equal(t, "fresh", result.Value)
+		assert.False(t, result.Stale)
+	})
+
+	t.Run("permanent", func(t *testing.T) {
+		t.Log("Func should return a permanent error instead of the fallback.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return nil, retry.Permanent(errors.New("not found"))
+		}
+		result := retry.Func(context.Background(), time.Millisecond, worker, retry.FallbackValue("cached"))
+		assert.Error(t, result.Err)
+		assert.Nil(t, result.Value)
+		assert.False(t, result.Stale)
+	})
+}
+
+func TestKeepLastValue(t *testing.T) {
+	t.Run("timeout", func(t *testing.T) {
+		t.Log("Func should return the last non-nil value with the timeout error.")
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			if calls <= 2 {
+				return calls, errors.New("partial")
+			}
+			return nil, errors.New("no value")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		result := retry.Func(ctx, time.Millisecond, worker, retry.KeepLastValue())
+		assert.Equal(t, 2, result.Value)
+		var err *retry.Error
+		if assert.True(t, errors.As(result.Err, &err)) {
+			assert.Equal(t, retry.ReasonTimeout, err.Reason())
+		}
+	})
+
+	t.Run("default", func(t *testing.T) {
+		t.Log("Func should not return a value with the timeout error by default.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return "partial", errors.New("partial")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		result := retry.Func(ctx, time.Millisecond, worker)
+		assert.Nil(t, result.Value)
+		assert.Error(t, result.Err)
+	})
+}
+
+func TestOnFinished(t *testing.T) {
+	t.Run("all", func(t *testing.T) {
+		t.Log("All should report a total close to the slowest worker function.")
+		sleeper := func(d time.Duration) retry.Worker {
+			return func(ctx context.Context) (interface{}, error) {
+				time.Sleep(d)
+				return nil, nil
+			}
+		}
+		workers := map[string]retry.Worker{"fast": sleeper(time.Millisecond), "slow": sleeper(30 * time.Millisecond)}
+		var total time.Duration
+		retry.All(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, retry.OnFinished(func(d time.Duration) {
+			total = d
+		}))
+		assert.GreaterOrEqual(t, int64(total), int64(30*time.Millisecond))
+		assert.Less(t, int64(total), int64(time.Second))
+	})
+
+	t.Run("clock", func(t *testing.T) {
+		t.Log("First should measure the total with the clock.")
+		var now int64
+		clock := func() time.Time {
+			return time.Unix(0, atomic.AddInt64(&now, int64(time.Second)))
+		}
+		worker := func(ctx context.Context) (interface{}, error) {
+			return "ok", nil
+		}
+		var calls int
+		var total time.Duration
+		result := retry.First(context.Background(), time.Millisecond, map[string]retry.Worker{"w": worker}, retry.MaxGoroutines,
+			retry.UseClock(clock), retry.OnFinished(func(d time.Duration) {
+				calls++
+				total = d
+			}))
+		assert.NoError(t, result.Err)
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, time.Duration(atomic.LoadInt64(&now))-time.Second, total)
+	})
+}
+
+func TestNoCancelLosers(t *testing.T) {
+	t.Run("running", func(t *testing.T) {
+		t.Log("First should leave the losers running until the context ends.")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var calls int32
+		stopped := make(chan struct{})
+		winner := func(ctx context.Context) (interface{}, error) {
+			for atomic.LoadInt32(&calls) == 0 {
+				time.Sleep(time.Millisecond)
+			}
+			return "winner", nil
+		}
+		loser := func(ctx context.Context) (interface{}, error) {
+			atomic.AddInt32(&calls, 1)
+			return nil, errors.New("warming")
+		}
+		workers := map[string]retry.Worker{"winner": winner, "loser": retry.WithCleanup(loser, func(retry.Result) {
+			close(stopped)
+		})}
+		result := retry.First(ctx, time.Millisecond, workers, retry.MaxGoroutines, retry.NoCancelLosers())
+		assert.NoError(t, result.Err)
+		returned := atomic.LoadInt32(&calls)
+		time.Sleep(20 * time.Millisecond)
+		assert.Greater(t, atomic.LoadInt32(&calls), returned)
+
+		cancel()
+		select {
+		case <-stopped:
+		case <-time.After(time.Second):
+			t.Error("loser did not stop after the context ended")
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrPoolClosed is the error of the results of worker functions given to a
+// Pool that was closed.
+var ErrPoolClosed = errors.New("retry: pool closed")
+
+// Pool is a set of goroutines that is reused by many RunAll calls, instead
+// of starting new goroutines on every All call.
+type Pool struct {
+	jobs    chan job
+	workers sync.WaitGroup
+
+	mu     sync.Mutex
+	closed bool
+	calls  sync.WaitGroup
+	once   sync.Once
+}
+
+// job is a worker function to be retried by a pool goroutine.
+type job struct {
+	ctx           context.Context
+	name          string
+	retryInterval time.Duration
+	worker        Worker
+	opts          *options
+	results       chan<- NamedResult
+}
+
+// NewPool starts a pool of concurrency goroutines. Values lower than 1 are
+// treated as 1. The pool must be closed with Close when no longer used.
+func NewPool(concurrency int) *Pool {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	p := Pool{jobs: make(chan job)}
+	p.workers.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer p.workers.Done()
+			for j := range p.jobs {
+				result := work(j.ctx, j.name, j.retryInterval, j.worker, j.opts)
+				j.results <- NamedResult{Name: j.name, Result: result}
+			}
+		}()
+	}
+
+	return &p
+}
+
+// RunAll is like All, but the worker functions are executed by the pool
+// goroutines. After the pool is closed, every result has ErrPoolClosed as
+// its error.
+func (p *Pool) RunAll(ctx context.Context, retryInterval time.Duration, workers map[string]Worker, opts ...Option) map[string]Result {
+	results := make(map[string]Result)
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		for name := range workers {
+			results[name] = Result{Err: ErrPoolClosed}
+		}
+		return results
+	}
+	p.calls.Add(1)
+	p.mu.Unlock()
+	defer p.calls.Done()
+
+	o := newOptions(opts)
+	ch := make(chan NamedResult, len(workers))
+	go func() {
+		for name, worker := range workers {
+			p.jobs <- job{ctx: ctx, name: name, retryInterval: retryInterval, worker: worker, opts: o, results: ch}
+		}
+	}()
+
+	for range workers {
+		result := <-ch
+		results[result.Name] = result.Result
+	}
+
+	return results
+}
+
+// Close rejects new RunAll calls, waits for the ones in flight to complete
+// and stops the pool goroutines.
+func (p *Pool) Close() {
+	p.once.Do(func() {
+		p.mu.Lock()
+		p.closed = true
+		p.mu.Unlock()
+
+		p.calls.Wait()
+		close(p.jobs)
+		p.workers.Wait()
+	})
+}
@@ -0,0 +1,103 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPool(t *testing.T) {
+	t.Run("runall", func(t *testing.T) {
+		t.Log("RunAll should return the results of all worker functions over many calls.")
+		p := retry.NewPool(2)
+		defer p.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+		ok := func(ctx context.Context) (interface{}, error) {
+			return "ok", nil
+		}
+		fail := func(ctx context.Context) (interface{}, error) {
+			return nil, errors.New("fail")
+		}
+		workers := map[string]retry.Worker{"ok1": ok, "ok2": ok, "ok3": ok}
+		for i := 0; i < 3; i++ {
+			results := p.RunAll(context.Background(), time.Millisecond, workers)
+			assert.Len(t, results, 3)
+			assert.Equal(t, 3, retry.CountSuccesses(results))
+		}
+		results := p.RunAll(ctx, time.Millisecond, map[string]retry.Worker{"ok": ok, "fail": fail})
+		assert.NoError(t, results["ok"].Err)
+		assert.Error(t, results["fail"].Err)
+	})
+
+	t.Run("close", func(t *testing.T) {
+		t.Log("Close should wait for RunAll calls in flight and reject new ones.")
+		p := retry.NewPool(1)
+		started := make(chan struct{})
+		release := make(chan struct{})
+		slow := func(ctx context.Context) (interface{}, error) {
+			close(started)
+			<-release
+			return "slow", nil
+		}
+		done := make(chan map[string]retry.Result)
+		go func() {
+			done <- p.RunAll(context.Background(), time.Millisecond, map[string]retry.Worker{"slow": slow})
+		}()
+		<-started
+
+		closed := make(chan struct{})
+		go func() {
+			p.Close()
+			close(closed)
+		}()
+		select {
+		case <-closed:
+			t.Fatal("Close should wait for the RunAll in flight")
+		case <-time.After(5 * time.Millisecond):
+		}
+
+		close(release)
+		results := <-done
+		assert.Equal(t, "slow", results["slow"].Value)
+		<-closed
+
+		results = p.RunAll(context.Background(), time.Millisecond, map[string]retry.Worker{"slow": slow})
+		assert.Equal(t, retry.ErrPoolClosed, results["slow"].Err)
+		p.Close()
+	})
+}
+
+// benchWorkers returns n worker functions that succeed at once.
+func benchWorkers(n int) map[string]retry.Worker {
+	workers := make(map[string]retry.Worker, n)
+	for i := 0; i < n; i++ {
+		workers[fmt.Sprint("worker", i)] = func(ctx context.Context) (interface{}, error) {
+			return nil, nil
+		}
+	}
+	return workers
+}
+
+func BenchmarkPool(b *testing.B) {
+	workers := benchWorkers(16)
+	p := retry.NewPool(4)
+	defer p.Close()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.RunAll(context.Background(), time.Millisecond, workers)
+	}
+}
+
+func BenchmarkAllWithPooling(b *testing.B) {
+	workers := benchWorkers(16)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		retry.All(context.Background(), time.Millisecond, workers, 4)
+	}
+}
@@ -0,0 +1,127 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// FirstPreferred is like First, but favors the preferred worker function.
+// When another worker function succeeds first, FirstPreferred waits up to
+// grace for the preferred one and returns its result if it succeeds in time.
+// Otherwise, or when the preferred worker function fails, the first
+// successful result is returned.
+func FirstPreferred(ctx context.Context, retryInterval time.Duration, workers map[string]Worker, preferred string, grace time.Duration, opts ...Option) Result {
+	start := time.Now()
+
+	ctx, cancel := withCancel(ctx)
+	defer cancel(ReasonWonByOther)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	o := newOptions(opts)
+	results := dispatch(ctx, done, retryInterval, workers, MaxGoroutines, o)
+	win := func(result Result) Result {
+		cancel(ReasonWonByOther)
+		drain(results, o.grace, nil)
+		return result
+	}
+
+	_, waiting := workers[preferred]
+	var first, stopped *Result
+	var timeout <-chan time.Time
+
+	errs := newErrorSet(o.maxErrors)
+	for {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				if stopped != nil {
+					return *stopped
+				}
+				errWork := errors.New("all worker functions failed")
+				return Result{Err: &Error{errWork: errs.wrap(errWork), since: time.Since(start), errs: errs.errs, reason: reasonOf(ctx)}}
+			}
+			if result.Name == preferred {
+				waiting = false
+			}
+			if !result.Result.Success() {
+				if result.Result.Err != nil {
+					errs.add(result.Name, result.Result.Err)
+				} else if stopped == nil {
+					placeholder := result.Result
+					stopped = &placeholder
+				}
+				if first != nil && !waiting {
+					return win(*first)
+				}
+				continue
+			}
+			if result.Name == preferred || !waiting {
+				return win(result.Result)
+			}
+			if first == nil {
+				winner := result.Result
+				first = &winner
+				t := time.NewTimer(grace)
+				defer t.Stop()
+				timeout = t.C
+			}
+		case <-timeout:
+			return win(*first)
+		}
+	}
+}
+
+// FirstAuthoritative is like First, but favors the values passing
+// isAuthoritative, such as the latest version in a quorum read. It returns
+// the first authoritative value as soon as it arrives, cancelling the other
+// worker functions. Otherwise, once all the worker functions completed, the
+// first successful result is returned.
+func FirstAuthoritative(ctx context.Context, retryInterval time.Duration, workers map[string]Worker, maxGs int, isAuthoritative func(value interface{}) bool, opts ...Option) Result {
+	start := time.Now()
+
+	ctx, cancel := withCancel(ctx)
+	defer cancel(ReasonWonByOther)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	o := newOptions(opts)
+	var first, stopped *Result
+	errs := newErrorSet(o.maxErrors)
+	results := dispatch(ctx, done, retryInterval, workers, maxGs, o)
+	for result := range results {
+		if result.Result.Err != nil {
+			errs.add(result.Name, result.Result.Err)
+			continue
+		}
+		if result.Result.Stopped {
+			if stopped == nil {
+				placeholder := result.Result
+				stopped = &placeholder
+			}
+			continue
+		}
+		if isAuthoritative(result.Value) {
+			cancel(ReasonWonByOther)
+			drain(results, o.grace, nil)
+			return result.Result
+		}
+		if first == nil {
+			winner := result.Result
+			first = &winner
+		}
+	}
+
+	if first != nil {
+		return *first
+	}
+	if stopped != nil {
+		return *stopped
+	}
+
+	errWork := errors.New("all worker functions failed")
+	return Result{Err: &Error{errWork: errs.wrap(errWork), since: time.Since(start), errs: errs.errs, reason: reasonOf(ctx)}}
+}
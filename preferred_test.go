@@ -0,0 +1,131 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFirstPreferred(t *testing.T) {
+	replica := func(ctx context.Context) (interface{}, error) {
+		return "replica", nil
+	}
+
+	t.Run("preferred", func(t *testing.T) {
+		t.Log("FirstPreferred should return the preferred result within the grace period.")
+		primary := func(ctx context.Context) (interface{}, error) {
+			time.Sleep(10 * time.Millisecond)
+			return "primary", nil
+		}
+		workers := map[string]retry.Worker{"primary": primary, "replica": replica}
+		result := retry.FirstPreferred(context.Background(), time.Millisecond, workers, "primary", time.Second)
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, "primary", result.Value)
+		}
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		t.Log("FirstPreferred should return the other result when the preferred worker function fails.")
+		primary := func(ctx context.Context) (interface{}, error) {
+			time.Sleep(10 * time.Millisecond)
+			return nil, retry.Permanent(errors.New("primary down"))
+		}
+		workers := map[string]retry.Worker{"primary": primary, "replica": replica}
+		start := time.Now()
+		result := retry.FirstPreferred(context.Background(), time.Millisecond, workers, "primary", 10*time.Second)
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, "replica", result.Value)
+		}
+		assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	})
+
+	t.Run("grace", func(t *testing.T) {
+		t.Log("FirstPreferred should return the other result when the grace period ends.")
+		primary := func(ctx context.Context) (interface{}, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		workers := map[string]retry.Worker{"primary": primary, "replica": replica}
+		result := retry.FirstPreferred(context.Background(), time.Millisecond, workers, "primary", 10*time.Millisecond)
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, "replica", result.Value)
+		}
+	})
+
+	t.Run("firstsuccess", func(t *testing.T) {
+		t.Log("FirstPreferred should return the first of the other successes when the grace period ends.")
+		primary := func(ctx context.Context) (interface{}, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		late := func(ctx context.Context) (interface{}, error) {
+			time.Sleep(5 * time.Millisecond)
+			return "late", nil
+		}
+		workers := map[string]retry.Worker{"primary": primary, "replica": replica, "late": late}
+		result := retry.FirstPreferred(context.Background(), time.Millisecond, workers, "primary", 20*time.Millisecond)
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, "replica", result.Value)
+		}
+	})
+
+	t.Run("allfail", func(t *testing.T) {
+		t.Log("FirstPreferred should return an error when all worker functions fail.")
+		fail := func(ctx context.Context) (interface{}, error) {
+			return nil, retry.Permanent(errors.New("down"))
+		}
+		workers := map[string]retry.Worker{"primary": fail, "replica": fail}
+		result := retry.FirstPreferred(context.Background(), time.Millisecond, workers, "primary", time.Second)
+		var err *retry.Error
+		if assert.True(t, errors.As(result.Err, &err)) {
+			assert.Len(t, err.Errors(), 2)
+		}
+	})
+}
+
+func TestFirstAuthoritative(t *testing.T) {
+	version := func(d time.Duration, v int) retry.Worker {
+		return func(ctx context.Context) (interface{}, error) {
+			select {
+			case <-time.After(d):
+				return v, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+	latest := func(value interface{}) bool {
+		return value.(int) == 3
+	}
+
+	t.Run("authoritative", func(t *testing.T) {
+		t.Log("FirstAuthoritative should return the slower authoritative value.")
+		workers := map[string]retry.Worker{
+			"stale":  version(0, 2),
+			"latest": version(10*time.Millisecond, 3),
+			"slow":   version(time.Hour, 3),
+		}
+		start := time.Now()
+		result := retry.FirstAuthoritative(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, latest)
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, 3, result.Value)
+		}
+		assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	})
+
+	t.Run("first", func(t *testing.T) {
+		t.Log("FirstAuthoritative should return the first success without an authoritative value.")
+		workers := map[string]retry.Worker{
+			"stale":  version(0, 2),
+			"staler": version(10*time.Millisecond, 1),
+		}
+		result := retry.FirstAuthoritative(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, latest)
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, 2, result.Value)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package retry
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// Progress is a result signaled by AllProgress, with how many of the worker
+// functions have completed so far.
+type Progress struct {
+	NamedResult
+	Completed int
+	Total     int
+}
+
+// AllProgress is like All, but signals each result over the returned channel
+// as soon as its worker function completes, along with the progress of the
+// whole call. Worker functions that fail, including the ones that time out,
+// count as completed, so the last progress always has Completed equal to
+// Total. The channel is closed once all the worker functions complete.
+func AllProgress(ctx context.Context, retryInterval time.Duration, workers map[string]Worker, maxGs int, opts ...Option) <-chan Progress {
+	total := len(workers)
+	progress := make(chan Progress, total)
+
+	go func() {
+		var completed int
+		for result := range dispatch(ctx, nil, retryInterval, workers, maxGs, newOptions(opts)) {
+			completed++
+			progress <- Progress{NamedResult: result, Completed: completed, Total: total}
+		}
+		close(progress)
+	}()
+
+	return progress
+}
+
+// AllTo is like All, but sends each result to out as soon as its worker
+// function completes, returning once all of them were sent. out is not
+// closed, the caller owns it. If the context ends while out is not ready,
+// AllTo stops sending and returns the context error, dropping the results
+// left.
+func AllTo(ctx context.Context, retryInterval time.Duration, workers map[string]Worker, maxGs int, out chan<- NamedResult, opts ...Option) error {
+	done := make(chan struct{})
+	defer close(done)
+
+	for result := range dispatch(ctx, done, retryInterval, workers, maxGs, newOptions(opts)) {
+		select {
+		case out <- result:
+			continue
+		default:
+		}
+
+		select {
+		case out <- result:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// AllByCompletion is like All, but returns the results ordered by when their
+// worker functions finished, the earliest first, using FinishedAt. Results
+// finishing at the same time are ordered by name.
+func AllByCompletion(ctx context.Context, retryInterval time.Duration, workers map[string]Worker, maxGs int, opts ...Option) []NamedResult {
+	results := All(ctx, retryInterval, workers, maxGs, opts...)
+
+	ordered := make([]NamedResult, 0, len(results))
+	for name, result := range results {
+		ordered = append(ordered, NamedResult{Name: name, Result: result})
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		a, b := ordered[i].Result.FinishedAt, ordered[j].Result.FinishedAt
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return ordered[i].Name < ordered[j].Name
+	})
+	return ordered
+}
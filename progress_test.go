@@ -0,0 +1,101 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllProgress(t *testing.T) {
+	t.Run("total", func(t *testing.T) {
+		t.Log("AllProgress should reach the total even when worker functions fail.")
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+		ok := func(ctx context.Context) (interface{}, error) {
+			return "ok", nil
+		}
+		fail := func(ctx context.Context) (interface{}, error) {
+			return nil, errors.New("fail")
+		}
+		workers := map[string]retry.Worker{"ok1": ok, "ok2": ok, "fail": fail}
+		var updates []retry.Progress
+		for p := range retry.AllProgress(ctx, time.Millisecond, workers, retry.MaxGoroutines) {
+			updates = append(updates, p)
+		}
+		if assert.Len(t, updates, 3) {
+			names := make(map[string]bool)
+			for i, p := range updates {
+				assert.Equal(t, i+1, p.Completed)
+				assert.Equal(t, 3, p.Total)
+				names[p.Name] = p.Success()
+			}
+			assert.Equal(t, map[string]bool{"ok1": true, "ok2": true, "fail": false}, names)
+		}
+	})
+}
+
+func TestAllTo(t *testing.T) {
+	t.Run("buffered", func(t *testing.T) {
+		t.Log("AllTo should send all the results to the channel without closing it.")
+		ok := func(ctx context.Context) (interface{}, error) {
+			return "ok", nil
+		}
+		workers := map[string]retry.Worker{"ok1": ok, "ok2": ok, "ok3": ok}
+		out := make(chan retry.NamedResult, 3)
+		err := retry.AllTo(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, out)
+		assert.NoError(t, err)
+		assert.Len(t, out, 3)
+		names := make(map[string]bool)
+		for i := 0; i < 3; i++ {
+			result := <-out
+			names[result.Name] = result.Success()
+		}
+		assert.Equal(t, map[string]bool{"ok1": true, "ok2": true, "ok3": true}, names)
+		out <- retry.NamedResult{}
+	})
+
+	t.Run("slow", func(t *testing.T) {
+		t.Log("AllTo should stop sending when the context ends before the consumer reads.")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		ok := func(ctx context.Context) (interface{}, error) {
+			return "ok", nil
+		}
+		workers := map[string]retry.Worker{"ok1": ok, "ok2": ok}
+		out := make(chan retry.NamedResult)
+		err := retry.AllTo(ctx, time.Millisecond, workers, retry.MaxGoroutines, out)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+}
+
+func TestAllByCompletion(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		t.Log("AllByCompletion should return the results in the order their worker functions finished.")
+		sleeper := func(d time.Duration) retry.Worker {
+			return func(ctx context.Context) (interface{}, error) {
+				time.Sleep(d)
+				return d, nil
+			}
+		}
+		workers := map[string]retry.Worker{
+			"a": sleeper(30 * time.Millisecond),
+			"b": sleeper(10 * time.Millisecond),
+			"c": sleeper(20 * time.Millisecond),
+			"d": sleeper(time.Millisecond),
+		}
+		results := retry.AllByCompletion(context.Background(), time.Millisecond, workers, retry.MaxGoroutines)
+		var names []string
+		for i, result := range results {
+			names = append(names, result.Name)
+			assert.NoError(t, result.Result.Err)
+			if i > 0 {
+				assert.False(t, result.Result.FinishedAt.Before(results[i-1].Result.FinishedAt))
+			}
+		}
+		assert.Equal(t, []string{"d", "b", "c", "a"}, names)
+	})
+}
@@ -0,0 +1,13 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// Majority is FirstN with k being more than half of the worker functions,
+// such as 3 of 5. It returns early with an error once so many worker
+// functions failed that a majority cannot succeed.
+func Majority(ctx context.Context, retryInterval time.Duration, workers map[string]Worker, maxGs int, opts ...Option) (map[string]Result, error) {
+	return FirstN(ctx, retryInterval, workers, maxGs, len(workers)/2+1, opts...)
+}
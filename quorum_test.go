@@ -0,0 +1,63 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMajority(t *testing.T) {
+	slow := func(ctx context.Context) (interface{}, error) {
+		select {
+		case <-time.After(time.Second):
+			return "slow", nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	fast := func(ctx context.Context) (interface{}, error) {
+		return "fast", nil
+	}
+	permanent := func(ctx context.Context) (interface{}, error) {
+		return nil, retry.Permanent(errors.New("replica down"))
+	}
+
+	t.Run("impossible", func(t *testing.T) {
+		t.Log("Majority should return as soon as three of five worker functions failed permanently.")
+		workers := map[string]retry.Worker{
+			"replica1": permanent,
+			"replica2": permanent,
+			"replica3": permanent,
+			"replica4": slow,
+			"replica5": slow,
+		}
+		start := time.Now()
+		results, err := retry.Majority(context.Background(), time.Millisecond, workers, retry.MaxGoroutines)
+		assert.Less(t, int64(time.Since(start)), int64(500*time.Millisecond))
+		assert.Empty(t, results)
+		var errRetry *retry.Error
+		if assert.True(t, errors.As(err, &errRetry)) {
+			assert.Equal(t, retry.ReasonFailFast, errRetry.Reason())
+			assert.Len(t, errRetry.Errors(), 3)
+			assert.Regexp(t, "0 of 3 worker functions succeeded", err.Error())
+		}
+	})
+
+	t.Run("majority", func(t *testing.T) {
+		t.Log("Majority should return the results once three of five worker functions succeeded.")
+		workers := map[string]retry.Worker{
+			"replica1": fast,
+			"replica2": fast,
+			"replica3": permanent,
+			"replica4": permanent,
+			"replica5": fast,
+		}
+		results, err := retry.Majority(context.Background(), time.Millisecond, workers, retry.MaxGoroutines)
+		assert.NoError(t, err)
+		assert.Len(t, results, 3)
+	})
+}
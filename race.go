@@ -0,0 +1,87 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RaceGroups calls First for each group of worker functions, returning one
+// result per group: the first successful worker function of the group, or
+// the First error. The losers of each group are cancelled as soon as the
+// group has a winner. maxGs is the number of worker function attempts
+// running at the same time across all the groups, taking turns like in
+// First so the failing worker functions of a group do not starve the other
+// groups; MaxGoroutines means no limit.
+func RaceGroups(ctx context.Context, retryInterval time.Duration, groups map[string]map[string]Worker, maxGs int, opts ...Option) map[string]Result {
+	if maxGs > 0 {
+		turns := make(chan struct{}, maxGs)
+		opts = append(opts[:len(opts):len(opts)], func(o *options) {
+			o.turns = turns
+		})
+	}
+
+	results := make(map[string]Result)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	wg.Add(len(groups))
+	for name, workers := range groups {
+		name, workers := name, workers
+		go func() {
+			defer wg.Done()
+			result := First(ctx, retryInterval, workers, MaxGoroutines, opts...)
+			mu.Lock()
+			defer mu.Unlock()
+			results[name] = result
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// Race calls each worker function once, bounded by the perAttempt timeout,
+// and returns the first success, cancelling the others. It is First without
+// retries, for hedged requests. When all the worker functions fail, the
+// error holds the error of each one, like First.
+func Race(ctx context.Context, workers map[string]Worker, perAttempt time.Duration, opts ...Option) Result {
+	bounded := make(map[string]Worker, len(workers))
+	for name, worker := range workers {
+		bounded[name] = WithTimeout(perAttempt)(worker)
+	}
+	return First(ctx, 0, bounded, MaxGoroutines, append(opts[:len(opts):len(opts)], once)...)
+}
+
+// Hedge calls the primary worker function once and, if it has not
+// succeeded within hedgeDelay, calls the backup worker function once too,
+// returning the first success and cancelling the other. The backup is
+// called right away when the primary fails before hedgeDelay, and never
+// when the primary succeeds before it. The worker functions are named
+// "primary" and "backup" in the errors.
+func Hedge(ctx context.Context, primary, backup Worker, hedgeDelay time.Duration, opts ...Option) Result {
+	failed := make(chan struct{})
+	var fail sync.Once
+
+	workers := map[string]Worker{
+		"primary": func(ctx context.Context) (interface{}, error) {
+			value, err := primary(ctx)
+			if err != nil {
+				fail.Do(func() { close(failed) })
+			}
+			return value, err
+		},
+		"backup": func(ctx context.Context) (interface{}, error) {
+			t := time.NewTimer(hedgeDelay)
+			defer t.Stop()
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-failed:
+			case <-t.C:
+			}
+			return backup(ctx)
+		},
+	}
+	return First(ctx, 0, workers, MaxGoroutines, append(opts[:len(opts):len(opts)], once)...)
+}
@@ -0,0 +1,207 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRaceGroups(t *testing.T) {
+	sleeper := func(d time.Duration, value string) retry.Worker {
+		return func(ctx context.Context) (interface{}, error) {
+			select {
+			case <-time.After(d):
+				return value, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	t.Run("noerror", func(t *testing.T) {
+		t.Log("RaceGroups should return the first success of each group.")
+		groups := map[string]map[string]retry.Worker{
+			"db": {
+				"db1": sleeper(time.Millisecond, "db1"),
+				"db2": sleeper(50*time.Millisecond, "db2"),
+				"db3": sleeper(60*time.Millisecond, "db3"),
+			},
+			"cache": {
+				"cache1": sleeper(50*time.Millisecond, "cache1"),
+				"cache2": sleeper(time.Millisecond, "cache2"),
+				"cache3": sleeper(60*time.Millisecond, "cache3"),
+			},
+		}
+		results := retry.RaceGroups(context.Background(), time.Millisecond, groups, retry.MaxGoroutines)
+		assert.Len(t, results, 2)
+		assert.Equal(t, "db1", results["db"].Value)
+		assert.Equal(t, "cache2", results["cache"].Value)
+	})
+
+	t.Run("shared", func(t *testing.T) {
+		t.Log("RaceGroups should share the goroutine limit across the groups.")
+		var running, peak int32
+		worker := func(ctx context.Context) (interface{}, error) {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(2 * time.Millisecond)
+			return nil, errors.New("not yet")
+		}
+		group := map[string]retry.Worker{"w1": worker, "w2": worker, "w3": worker}
+		groups := map[string]map[string]retry.Worker{"a": group, "b": group}
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		results := retry.RaceGroups(ctx, time.Millisecond, groups, 2)
+		assert.Len(t, results, 2)
+		assert.Error(t, results["a"].Err)
+		assert.Error(t, results["b"].Err)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&peak))
+	})
+
+	t.Run("starvation", func(t *testing.T) {
+		t.Log("RaceGroups should not let a group that keeps failing starve the other groups.")
+		failing := func(ctx context.Context) (interface{}, error) {
+			return nil, errors.New("not yet")
+		}
+		called := make(chan time.Duration, 1)
+		start := time.Now()
+		ok := func(ctx context.Context) (interface{}, error) {
+			called <- time.Since(start)
+			return "ok", nil
+		}
+		groups := map[string]map[string]retry.Worker{"failing": {}, "ok": {"ok": ok}}
+		for i := 0; i < 10; i++ {
+			groups["failing"][fmt.Sprint("f", i)] = failing
+			groups["ok"][fmt.Sprint("f", i)] = failing
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		results := retry.RaceGroups(ctx, time.Millisecond, groups, 2)
+		assert.Equal(t, "ok", results["ok"].Value)
+		assert.Error(t, results["failing"].Err)
+		select {
+		case d := <-called:
+			assert.Less(t, int64(d), int64(100*time.Millisecond))
+		default:
+			t.Error("the ok group never ran")
+		}
+	})
+}
+
+func TestRace(t *testing.T) {
+	t.Run("hedged", func(t *testing.T) {
+		t.Log("Race should return the first success and call each worker function once.")
+		var calls int32
+		slow := func(ctx context.Context) (interface{}, error) {
+			atomic.AddInt32(&calls, 1)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		hedge := func(ctx context.Context) (interface{}, error) {
+			atomic.AddInt32(&calls, 1)
+			time.Sleep(5 * time.Millisecond)
+			return "hedge", nil
+		}
+		workers := map[string]retry.Worker{"primary": slow, "hedge": hedge}
+		result := retry.Race(context.Background(), workers, time.Second)
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, "hedge", result.Value)
+		}
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("allfail", func(t *testing.T) {
+		t.Log("Race should return the error of each worker function when all fail their attempt.")
+		var calls int32
+		slow := func(ctx context.Context) (interface{}, error) {
+			atomic.AddInt32(&calls, 1)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		fail := func(ctx context.Context) (interface{}, error) {
+			atomic.AddInt32(&calls, 1)
+			return nil, errors.New("down")
+		}
+		workers := map[string]retry.Worker{"slow": slow, "fail": fail}
+		result := retry.Race(context.Background(), workers, 10*time.Millisecond)
+		var err *retry.Error
+		if assert.True(t, errors.As(result.Err, &err)) {
+			errs := err.Errors()
+			assert.True(t, errors.Is(errs["slow"], context.DeadlineExceeded))
+			assert.EqualError(t, errs["fail"], "down")
+		}
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+}
+
+func TestHedge(t *testing.T) {
+	t.Run("fastprimary", func(t *testing.T) {
+		t.Log("Hedge should not call the backup when the primary succeeds before the hedge delay.")
+		var backups int32
+		primary := func(ctx context.Context) (interface{}, error) {
+			return "primary", nil
+		}
+		backup := func(ctx context.Context) (interface{}, error) {
+			atomic.AddInt32(&backups, 1)
+			return "backup", nil
+		}
+		result := retry.Hedge(context.Background(), primary, backup, 50*time.Millisecond)
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, "primary", result.Value)
+		}
+		time.Sleep(60 * time.Millisecond)
+		assert.Equal(t, int32(0), atomic.LoadInt32(&backups))
+	})
+
+	t.Run("slowprimary", func(t *testing.T) {
+		t.Log("Hedge should return the backup when the primary is slower than the hedge delay, cancelling the primary.")
+		cancelled := make(chan struct{})
+		primary := func(ctx context.Context) (interface{}, error) {
+			<-ctx.Done()
+			close(cancelled)
+			return nil, ctx.Err()
+		}
+		backup := func(ctx context.Context) (interface{}, error) {
+			return "backup", nil
+		}
+		start := time.Now()
+		result := retry.Hedge(context.Background(), primary, backup, 20*time.Millisecond)
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, "backup", result.Value)
+		}
+		assert.GreaterOrEqual(t, int64(time.Since(start)), int64(20*time.Millisecond))
+		select {
+		case <-cancelled:
+		case <-time.After(time.Second):
+			t.Error("primary was not cancelled")
+		}
+	})
+
+	t.Run("primaryfails", func(t *testing.T) {
+		t.Log("Hedge should call the backup right away when the primary fails.")
+		primary := func(ctx context.Context) (interface{}, error) {
+			return nil, errors.New("down")
+		}
+		backup := func(ctx context.Context) (interface{}, error) {
+			return "backup", nil
+		}
+		start := time.Now()
+		result := retry.Hedge(context.Background(), primary, backup, time.Second)
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, "backup", result.Value)
+		}
+		assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	})
+}
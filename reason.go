@@ -0,0 +1,129 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Reason tells why a worker function stopped being retried.
+type Reason int
+
+// Reasons a worker function stopped being retried. The zero value is used by
+// errors aggregating many worker functions when the context has not ended.
+const (
+	// ReasonTimeout means the context deadline was exceeded.
+	ReasonTimeout Reason = iota + 1
+
+	// ReasonCanceled means the context was cancelled by the caller.
+	ReasonCanceled
+
+	// ReasonWonByOther means First or FirstN cancelled the worker function
+	// because other worker functions succeeded.
+	ReasonWonByOther
+
+	// ReasonMaxAttempts means the maximum number of attempts was reached.
+	ReasonMaxAttempts
+
+	// ReasonPermanent means the worker function error must not be retried.
+	ReasonPermanent
+
+	// ReasonFailFast means All or Group cancelled the worker function
+	// because another worker function failed permanently, see FailFast, or
+	// FirstN and Majority cancelled it because too many worker functions
+	// failed for enough of them to succeed.
+	ReasonFailFast
+
+	// ReasonAborted means the worker function returned an error made by
+	// Abort, or All cancelled it because another worker function did.
+	ReasonAborted
+)
+
+// String implements the fmt.Stringer interface.
+func (r Reason) String() string {
+	switch r {
+	case ReasonTimeout:
+		return "timeout"
+	case ReasonCanceled:
+		return "canceled"
+	case ReasonWonByOther:
+		return "won by other"
+	case ReasonMaxAttempts:
+		return "max attempts"
+	case ReasonPermanent:
+		return "permanent"
+	case ReasonFailFast:
+		return "fail fast"
+	case ReasonAborted:
+		return "aborted"
+	}
+	return "unknown"
+}
+
+// canceler is stored in the contexts cancelled internally, to remember the
+// caller's context and why the internal context was cancelled.
+type canceler struct {
+	caller context.Context
+
+	mu     sync.Mutex
+	reason Reason
+}
+
+// cancelerKey is the context key used to store the canceler.
+type cancelerKey struct{}
+
+// withCancel returns a cancelable copy of the context that remembers the
+// caller's context, so worker functions cancelled internally, for example
+// because another one won, are not reported as timeouts. The reason of the
+// first cancel call is kept.
+func withCancel(ctx context.Context) (context.Context, func(Reason)) {
+	c := canceler{caller: ctx}
+	cctx, cancel := context.WithCancel(context.WithValue(ctx, cancelerKey{}, &c))
+	return cctx, func(reason Reason) {
+		c.mu.Lock()
+		if c.reason == 0 {
+			c.reason = reason
+		}
+		c.mu.Unlock()
+		cancel()
+	}
+}
+
+// callerDone reports whether the caller's context ended, as opposed to
+// being cancelled internally. A deadline set by ExtendOnProgress passing
+// counts as the caller's context ending.
+func callerDone(ctx context.Context) bool {
+	if e, ok := ctx.Value(extensionKey{}).(*extension); ok && e.hasExpired() {
+		return true
+	}
+	if c, ok := ctx.Value(cancelerKey{}).(*canceler); ok {
+		return c.caller.Err() != nil
+	}
+	return ctx.Err() != nil
+}
+
+// reasonOf returns why the context ended, or zero if it has not.
+func reasonOf(ctx context.Context) Reason {
+	switch {
+	case ctx.Err() == nil:
+		return 0
+	case !callerDone(ctx):
+		c := ctx.Value(cancelerKey{}).(*canceler)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.reason
+	case ctx.Err() == context.DeadlineExceeded:
+		return ReasonTimeout
+	}
+	return ReasonCanceled
+}
+
+// errorReason returns the reason of the error, or zero if it is not an
+// *Error.
+func errorReason(err error) Reason {
+	var errRetry *Error
+	if errors.As(err, &errRetry) {
+		return errRetry.reason
+	}
+	return 0
+}
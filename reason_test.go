@@ -0,0 +1,81 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReason(t *testing.T) {
+	fail := func(ctx context.Context) (interface{}, error) {
+		return nil, errors.New("fail")
+	}
+	reason := func(t *testing.T, err error) retry.Reason {
+		var errRetry *retry.Error
+		if !assert.True(t, errors.As(err, &errRetry)) {
+			return 0
+		}
+		return errRetry.Reason()
+	}
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Log("Func should report ReasonTimeout when the context deadline is exceeded.")
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Millisecond)
+		defer cancel()
+		result := retry.Func(ctx, time.Millisecond, fail)
+		assert.Equal(t, retry.ReasonTimeout, reason(t, result.Err))
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		t.Log("Func should report ReasonCanceled when the context is cancelled.")
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		result := retry.Func(ctx, time.Millisecond, fail)
+		assert.Equal(t, retry.ReasonCanceled, reason(t, result.Err))
+	})
+
+	t.Run("maxattempts", func(t *testing.T) {
+		t.Log("Func should report ReasonMaxAttempts when it gives up.")
+		result := retry.Func(context.Background(), time.Nanosecond, fail, retry.MaxAttempts(2))
+		assert.Equal(t, retry.ReasonMaxAttempts, reason(t, result.Err))
+	})
+
+	t.Run("permanent", func(t *testing.T) {
+		t.Log("Func should report ReasonPermanent when the error must not be retried.")
+		breaker := retry.CircuitBreaker(fail, retry.BreakerOptions{Cooldown: time.Hour})
+		result := retry.Func(context.Background(), time.Nanosecond, breaker)
+		assert.Equal(t, retry.ReasonPermanent, reason(t, result.Err))
+	})
+
+	t.Run("wonbyother", func(t *testing.T) {
+		t.Log("First should cancel the losers with ReasonWonByOther.")
+		started := make(chan struct{})
+		winner := func(ctx context.Context) (interface{}, error) {
+			<-started
+			return "ok", nil
+		}
+		lost := make(chan error, 1)
+		loser := func(ctx context.Context) (interface{}, error) {
+			close(started)
+			result := retry.Func(ctx, time.Millisecond, fail)
+			lost <- result.Err
+			return nil, result.Err
+		}
+		workers := map[string]retry.Worker{"winner": winner, "loser": loser}
+		result := retry.First(context.Background(), time.Millisecond, workers, retry.MaxGoroutines)
+		assert.NoError(t, result.Err)
+		err := <-lost
+		assert.Equal(t, retry.ReasonWonByOther, reason(t, err))
+		assert.Regexp(t, "cancelled by another worker function", err.Error())
+	})
+
+	t.Run("string", func(t *testing.T) {
+		t.Log("Reason should describe itself.")
+		assert.Equal(t, "won by other", retry.ReasonWonByOther.String())
+		assert.Equal(t, "unknown", retry.Reason(0).String())
+	})
+}
@@ -0,0 +1,32 @@
+package retry
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// Repeat calls All with k copies of the worker function, running
+// concurrency of them at the same time, and returns the k results in call
+// order. Each copy is retried every retry interval like in All, unless the
+// options say otherwise: MaxAttempts(1) makes each copy a single attempt.
+// The copies are named by their index, from "0" to k-1, in the errors. A k
+// of zero or less returns no results.
+func Repeat(ctx context.Context, retryInterval time.Duration, worker Worker, k int, concurrency int, opts ...Option) []Result {
+	if k <= 0 {
+		return []Result{}
+	}
+
+	workers := make(map[string]Worker, k)
+	for i := 0; i < k; i++ {
+		workers[strconv.Itoa(i)] = worker
+	}
+
+	results := All(ctx, retryInterval, workers, concurrency, opts...)
+
+	ordered := make([]Result, k)
+	for i := range ordered {
+		ordered[i] = results[strconv.Itoa(i)]
+	}
+	return ordered
+}
@@ -0,0 +1,65 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepeat(t *testing.T) {
+	t.Run("results", func(t *testing.T) {
+		t.Log("Repeat should return exactly k results, running at most concurrency worker functions at once.")
+		var calls, running, peak int32
+		worker := func(ctx context.Context) (interface{}, error) {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			return atomic.AddInt32(&calls, 1), nil
+		}
+		results := retry.Repeat(context.Background(), time.Millisecond, worker, 10, 3)
+		assert.Len(t, results, 10)
+		for _, result := range results {
+			assert.NoError(t, result.Err)
+		}
+		assert.Equal(t, int32(10), atomic.LoadInt32(&calls))
+		assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(3))
+	})
+
+	t.Run("once", func(t *testing.T) {
+		t.Log("Repeat should call each copy once with MaxAttempts(1).")
+		var calls int32
+		worker := func(ctx context.Context) (interface{}, error) {
+			atomic.AddInt32(&calls, 1)
+			return nil, errors.New("sampled failure")
+		}
+		results := retry.Repeat(context.Background(), time.Millisecond, worker, 5, retry.MaxGoroutines, retry.MaxAttempts(1))
+		assert.Len(t, results, 5)
+		for _, result := range results {
+			var err *retry.Error
+			if assert.True(t, errors.As(result.Err, &err)) {
+				assert.Equal(t, retry.ReasonMaxAttempts, err.Reason())
+			}
+		}
+		assert.Equal(t, int32(5), atomic.LoadInt32(&calls))
+	})
+	t.Run("negative", func(t *testing.T) {
+		t.Log("Repeat should return no results for k of zero or less.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			t.Error("worker function called")
+			return nil, nil
+		}
+		assert.Empty(t, retry.Repeat(context.Background(), time.Millisecond, worker, 0, retry.MaxGoroutines))
+		assert.Empty(t, retry.Repeat(context.Background(), time.Millisecond, worker, -1, retry.MaxGoroutines))
+	})
+}
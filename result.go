@@ -0,0 +1,37 @@
+package retry
+
+import "sort"
+
+// Success reports whether the worker function succeeded. A result stopped
+// by StopWith is not a success.
+func (r Result) Success() bool {
+	return r.Err == nil && !r.Stopped
+}
+
+// Failed reports whether the worker function failed.
+func (r Result) Failed() bool {
+	return r.Err != nil
+}
+
+// CountSuccesses returns the number of successful results.
+func CountSuccesses(results map[string]Result) int {
+	var n int
+	for _, result := range results {
+		if result.Success() {
+			n++
+		}
+	}
+	return n
+}
+
+// FailedNames returns the sorted names of the failed results.
+func FailedNames(results map[string]Result) []string {
+	var names []string
+	for name, result := range results {
+		if result.Failed() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
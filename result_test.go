@@ -0,0 +1,38 @@
+package retry_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResult(t *testing.T) {
+	results := map[string]retry.Result{
+		"worker1": {Value: "ok"},
+		"worker2": {Err: errors.New("worker2 error")},
+		"worker3": {},
+		"worker4": {Err: errors.New("worker4 error")},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Log("Success and Failed should report whether the result has an error.")
+		assert.True(t, results["worker1"].Success())
+		assert.False(t, results["worker1"].Failed())
+		assert.False(t, results["worker2"].Success())
+		assert.True(t, results["worker2"].Failed())
+	})
+
+	t.Run("count", func(t *testing.T) {
+		t.Log("CountSuccesses should count the results without error.")
+		assert.Equal(t, 2, retry.CountSuccesses(results))
+		assert.Equal(t, 0, retry.CountSuccesses(nil))
+	})
+
+	t.Run("failednames", func(t *testing.T) {
+		t.Log("FailedNames should return the sorted names of the results with error.")
+		assert.Equal(t, []string{"worker2", "worker4"}, retry.FailedNames(results))
+		assert.Empty(t, retry.FailedNames(nil))
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -15,22 +16,59 @@ type Worker func(ctx context.Context) (interface{}, error)
 type Result struct {
 	Value interface{}
 	Err   error
+
+	// StartedAt and FinishedAt are when the work of the worker function
+	// began and ended, read from the clock. They are zero for results not
+	// from a single worker function, like the First error when all failed.
+	StartedAt  time.Time
+	FinishedAt time.Time
+
+	// Stale reports that Value is the FallbackValue, returned instead of an
+	// error because the context ended. Err is nil then.
+	Stale bool
+
+	// Stopped reports that Value came from StopWith, so it is not a
+	// canonical success. Err is nil then.
+	Stopped bool
 }
 
 // Error informs that a cancellation took place before the worker
-// function returned successfully.
+// function returned successfully, or that the worker function returned an
+// error that must not be retried.
 type Error struct {
-	errWork error
-	since   time.Duration
+	name     string
+	errWork  error
+	since    time.Duration
+	errs     map[string]error
+	deadline time.Time
+	reason   Reason
+	attempts int
 }
 
 // Error implements the error interface and returns information about
-// the timeout error.
+// the timeout error, starting with the worker function name when it has
+// one.
 func (err *Error) Error() string {
+	msg := fmt.Sprintf("context cancelled after %v", err.since)
+	switch {
+	case err.reason == ReasonMaxAttempts:
+		msg = fmt.Sprintf("gave up after %d attempts in %v", err.attempts, err.since)
+	case err.reason == ReasonPermanent:
+		msg = fmt.Sprintf("retry stopped after %v", err.since)
+	case err.reason == ReasonAborted:
+		msg = fmt.Sprintf("aborted after %v", err.since)
+	case err.reason == ReasonWonByOther:
+		msg = fmt.Sprintf("cancelled by another worker function after %v", err.since)
+	case !err.deadline.IsZero():
+		msg = fmt.Sprintf("deadline %v exceeded after %v", err.deadline, err.since)
+	}
+	if err.name != "" {
+		msg = fmt.Sprintf("worker %q %s", err.name, msg)
+	}
 	if err.errWork != nil {
-		return fmt.Sprintf("context cancelled after %v : %s", err.since, err.errWork)
+		return fmt.Sprintf("%s : %s", msg, err.errWork)
 	}
-	return fmt.Sprintf("context cancelled after %v", err.since)
+	return msg
 }
 
 // Unwrap returns the context error, if any
@@ -38,108 +76,644 @@ func (err *Error) Unwrap() error {
 	return err.errWork
 }
 
+// Reason returns why the worker function stopped being retried.
+func (err *Error) Reason() Reason {
+	return err.reason
+}
+
+// Errors returns the last error of each failed worker function, keyed by
+// name, when the error was returned by First or FirstN. It returns nil
+// otherwise. See MaxErrors to bound how many are kept.
+func (err *Error) Errors() map[string]error {
+	return err.errs
+}
+
 // Constants that represent the max goroutines to use.
 const (
 	MaxGoroutines = 0
 )
 
+// attemptKey is the context key used to store the attemptState.
+type attemptKey struct{}
+
+// attemptState describes the worker function call in progress.
+type attemptState struct {
+	number  int
+	left    int
+	lastErr error
+}
+
+// AttemptFromContext returns the attempt number of the current worker
+// function call. The first attempt is 1. It returns 0 if the context
+// was not provided by Func.
+func AttemptFromContext(ctx context.Context) int {
+	a, _ := ctx.Value(attemptKey{}).(attemptState)
+	return a.number
+}
+
+// LastErrorFromContext returns the error of the previous attempt of the
+// current worker function call. It returns nil on the first attempt, or if
+// the context was not provided by Func.
+func LastErrorFromContext(ctx context.Context) error {
+	a, _ := ctx.Value(attemptKey{}).(attemptState)
+	return a.lastErr
+}
+
 // Func calls the worker function every retry interval until the worker
-// function succeeds or the context times out.
-func Func(ctx context.Context, retryInterval time.Duration, worker Worker) Result {
+// function succeeds or the context times out. Func stops early when the
+// worker function returns an error that must not be retried, see RetryIf.
+// The context passed to the worker function carries the attempt number, the
+// budget left and the previous error, see AttemptFromContext,
+// BudgetFromContext and LastErrorFromContext.
+func Func(ctx context.Context, retryInterval time.Duration, worker Worker, opts ...Option) Result {
+	o := newOptions(opts)
+	defer o.finished(o.now())
+	return work(ctx, "", retryInterval, worker, o)
+}
+
+// FuncDeadline is like Func but gives up at the deadline, even if the
+// context lives longer. A deadline in the past returns without calling the
+// worker function.
+func FuncDeadline(ctx context.Context, retryInterval time.Duration, deadline time.Time, worker Worker, opts ...Option) Result {
+	dctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	result := Func(dctx, retryInterval, worker, opts...)
+	if err, ok := result.Err.(*Error); ok && ctx.Err() == nil {
+		err.deadline = deadline
+	}
+	return result
+}
+
+// Try is like Func, but calls the worker function exactly once, never
+// retrying it. A failed worker function has an error with the
+// ReasonMaxAttempts reason wrapping its error, and a context that already
+// ended returns an error without calling it.
+func Try(ctx context.Context, worker Worker, opts ...Option) Result {
+	return Func(ctx, 0, worker, append(opts[:len(opts):len(opts)], once)...)
+}
+
+// FuncValue is like Func but returns the result value and error directly.
+func FuncValue(ctx context.Context, retryInterval time.Duration, worker Worker, opts ...Option) (interface{}, error) {
+	result := Func(ctx, retryInterval, worker, opts...)
+	return result.Value, result.Err
+}
+
+// work implements Func for the named worker function, running the cleanup
+// functions registered by its calls once the result is known.
+func work(ctx context.Context, name string, retryInterval time.Duration, worker Worker, o *options) Result {
+	var c cleanups
+	startedAt := o.now()
+	result := attempts(context.WithValue(ctx, cleanupKey{}, &c), name, retryInterval, worker, o)
+	result.StartedAt, result.FinishedAt = startedAt, o.now()
+	if err, ok := result.Err.(*Error); ok {
+		err.name = name
+	}
+	c.run(result)
+	return result
+}
+
+// attempts calls the worker function until it succeeds, it must not be
+// retried anymore or the context ends.
+func attempts(ctx context.Context, name string, retryInterval time.Duration, worker Worker, o *options) Result {
 	var retry *time.Timer
-	start := time.Now()
+	var lastValue interface{}
+	start := o.now()
+
+	var ext *extension
+	if o.extend != nil {
+		var stop func()
+		ext, stop = o.extend.with(ctx)
+		defer stop()
+		ctx = ext
+	}
+	retryInterval = o.interval(ctx, retryInterval)
+
+	timeout := func(attempt int, err error) Result {
+		if callerDone(ctx) {
+			o.log("timeout", name, attempt, err)
+			if o.onTimeout != nil {
+				o.onTimeout(err)
+			}
+			if o.useFallback {
+				return Result{Value: o.fallback, Stale: true}
+			}
+		}
+		result := Result{Err: &Error{errWork: err, since: o.now().Sub(start), reason: reasonOf(ctx)}}
+		if o.keepLast || o.doneWhen != nil {
+			result.Value = lastValue
+		}
+		return result
+	}
 
 	if ctx.Err() != nil {
-		return Result{Err: &Error{errWork: nil, since: time.Since(start)}}
+		return timeout(0, nil)
 	}
 
-	for {
-		value, err := worker(ctx)
-		if err == nil {
+	if o.jitter != nil && !sleep(ctx, o.jitter.delay()) {
+		return timeout(0, nil)
+	}
+
+	if o.stagger != nil {
+		n := atomic.AddInt64(o.stagger, 1) - 1
+		if !sleep(ctx, time.Duration(n)*retryInterval) {
+			return timeout(0, nil)
+		}
+	}
+
+	if o.slots != nil {
+		select {
+		case o.slots <- struct{}{}:
+			defer func() { <-o.slots }()
+		case <-ctx.Done():
+			return timeout(0, nil)
+		}
+	}
+
+	var lastErr error
+	var failures int
+	var slept time.Duration
+	for attempt := 1; ; attempt++ {
+		if o.controller != nil && !o.controller.wait(ctx) {
+			return timeout(attempt-1, lastErr)
+		}
+		releaseTurn := func() {}
+		if o.turns != nil {
+			var ok bool
+			if releaseTurn, ok = acquire(ctx, o.turns); !ok {
+				return timeout(attempt-1, lastErr)
+			}
+		}
+		gctx, releaseGlobal, ok := acquireGlobal(ctx)
+		if !ok {
+			releaseTurn()
+			return timeout(attempt-1, lastErr)
+		}
+		release := func() {
+			releaseGlobal()
+			releaseTurn()
+		}
+		if o.gauge != nil {
+			release = o.gauge.track(release)
+		}
+		left := -1
+		switch {
+		case o.once:
+			left = 0
+		case o.maxAttempts > 0:
+			left = o.maxAttempts - failures - 1
+		}
+		actx := context.WithValue(gctx, attemptKey{}, attemptState{number: attempt, left: left, lastErr: lastErr})
+		called := o.now()
+		var value interface{}
+		var err error
+		if o.hardTimeout {
+			result, ok := abandon(actx, worker)
+			if !ok {
+				release()
+				return timeout(attempt, lastErr)
+			}
+			value, err = result.Value, result.Err
+		} else {
+			value, err = worker(actx)
+		}
+		if err == nil && value == nil && o.nonNil {
+			err = ErrNilValue
+		}
+		latency := o.now().Sub(called)
+		release()
+		lastErr = err
+		o.log("attempt", name, attempt, err)
+		if o.observer != nil {
+			o.observer.AttemptCompleted(name, attempt, latency, err)
+		}
+		if value != nil || !o.keepLast {
+			lastValue = value
+		}
+		var stop *StopError
+		if errors.As(err, &stop) {
+			o.log("stopped", name, attempt, err)
+			return Result{Value: stop.value, Stopped: true}
+		}
+		if o.doneWhen != nil {
+			if o.doneWhen(value, err) {
+				return Result{Value: value, Err: err}
+			}
+		} else if err == nil {
 			return Result{Value: value}
 		}
 
+		if o.once {
+			o.log("giveup", name, attempt, err)
+			return Result{Err: &Error{errWork: err, since: o.now().Sub(start), reason: ReasonMaxAttempts, attempts: attempt}}
+		}
+
+		delay := retryInterval
+		if errors.Is(err, ErrProgress) {
+			failures = 0
+			if ext != nil {
+				ext.progress()
+			}
+		} else {
+			failures++
+
+			var abort *AbortError
+			if errors.As(err, &abort) {
+				o.log("abort", name, attempt, err)
+				return Result{Err: &Error{errWork: err, since: o.now().Sub(start), reason: ReasonAborted}}
+			}
+
+			if err != nil && !o.retryable(err) {
+				o.log("stop", name, attempt, err)
+				return Result{Err: &Error{errWork: err, since: o.now().Sub(start), reason: ReasonPermanent}}
+			}
+
+			if o.maxAttempts > 0 && failures >= o.maxAttempts {
+				o.log("giveup", name, attempt, err)
+				return Result{Err: &Error{errWork: err, since: o.now().Sub(start), reason: ReasonMaxAttempts, attempts: attempt}}
+			}
+
+			delay = o.delay(retryInterval, Attempt{Number: failures, Latency: latency, Err: err, Slept: slept})
+			var after *RetryAfterError
+			if delay != Stop && errors.As(err, &after) {
+				delay = after.delay
+			}
+			if delay == Stop {
+				o.log("giveup", name, attempt, err)
+				return Result{Err: &Error{errWork: err, since: o.now().Sub(start), reason: ReasonMaxAttempts, attempts: attempt}}
+			}
+		}
+		slept += delay
+
 		if ctx.Err() != nil {
-			return Result{Err: &Error{errWork: err, since: time.Since(start)}}
+			return timeout(attempt, err)
 		}
 
-		if retry == nil {
-			retry = time.NewTimer(retryInterval)
+		var wait <-chan time.Time
+		switch {
+		case o.scheduler != nil:
+			wait = o.scheduler.After(delay)
+		case retry == nil:
+			retry = time.NewTimer(delay)
+			wait = retry.C
+		default:
+			retry.Reset(delay)
+			wait = retry.C
 		}
 
 		select {
 		case <-ctx.Done():
-			retry.Stop()
-			return Result{Err: &Error{errWork: err, since: time.Since(start)}}
-		case <-retry.C:
-			retry.Reset(retryInterval)
+			if retry != nil {
+				retry.Stop()
+			}
+			return timeout(attempt, err)
+		case <-wait:
+			o.log("retry", name, attempt, err)
 		}
 	}
 }
 
+// abandon calls the worker function in its own goroutine and waits for it,
+// returning false without waiting any longer if the context ends first.
+func abandon(ctx context.Context, worker Worker) (Result, bool) {
+	done := make(chan Result, 1)
+	go func() {
+		value, err := worker(ctx)
+		done <- Result{Value: value, Err: err}
+	}()
+
+	select {
+	case result := <-done:
+		return result, true
+	case <-ctx.Done():
+		return Result{}, false
+	}
+}
+
+// sleep waits for the duration, returning false if the context ends first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
 // All calls all the worker functions every retry interval until the worker
 // functions succeeds or the context times out. maxGs represents the number
 // of goroutines to run simultaneously to execute all the worker functions.
-func All(ctx context.Context, retryInterval time.Duration, workers map[string]Worker, maxGs int) map[string]Result {
+// A worker function returning an error made by Abort cancels the others.
+func All(ctx context.Context, retryInterval time.Duration, workers map[string]Worker, maxGs int, opts ...Option) map[string]Result {
+	o := newOptions(opts)
+	defer o.finished(o.now())
+	workers, aliases := o.coalesce(workers)
 	results := make(map[string]Result)
 
-	switch {
-	case maxGs <= 0 || maxGs >= len(workers):
-		for result := range workMap(ctx, retryInterval, workers) {
-			results[result.name] = result.Result
+	ctx, cancel := withCancel(ctx)
+	defer cancel(ReasonCanceled)
+
+	for result := range dispatch(ctx, nil, retryInterval, workers, maxGs, o) {
+		results[result.Name] = result.Result
+		for _, name := range aliases[result.Name] {
+			results[name] = alias(result.Result, name)
 		}
-	default:
-		for result := range workPool(ctx, retryInterval, workers, maxGs) {
-			results[result.name] = result.Result
+		switch errorReason(result.Result.Err) {
+		case ReasonPermanent:
+			if o.failFast {
+				cancel(ReasonFailFast)
+			}
+		case ReasonAborted:
+			cancel(ReasonAborted)
 		}
 	}
 
 	return results
 }
 
+// AllOnce is like All, but calls each worker function exactly once, never
+// retrying it, and returns whatever came back. Failed worker functions have
+// an error with the ReasonMaxAttempts reason wrapping their error.
+func AllOnce(ctx context.Context, workers map[string]Worker, maxGs int, opts ...Option) map[string]Result {
+	return All(ctx, 0, workers, maxGs, append(opts[:len(opts):len(opts)], once)...)
+}
+
+// once makes the worker functions be called only once.
+func once(o *options) {
+	o.once = true
+}
+
 // First calls all the worker functions every retry interval until the worker
 // functions succeeds or the context times out. Once the first worker function
-// succeeds, this function will return that result. maxGs represents the number
-// of goroutines to run simultaneously to execute all the worker functions.
-func First(ctx context.Context, retryInterval time.Duration, workers map[string]Worker, maxGs int) Result {
+// succeeds, this function will return that result. A result stopped by
+// StopWith is not a success, it is only returned instead of the error when
+// no worker function succeeds. maxGs represents the number of worker
+// functions to run simultaneously. To be fair when there are more worker
+// functions than maxGs, every worker function has its own goroutine and
+// they take turns for each attempt, instead of a few worker functions that
+// keep failing holding on to the goroutines.
+func First(ctx context.Context, retryInterval time.Duration, workers map[string]Worker, maxGs int, opts ...Option) Result {
+	return first(ctx, retryInterval, workers, maxGs, newOptions(opts), nil).Result
+}
+
+// first implements First, returning the winner with its name, or the error
+// without a name. It calls seen, if not nil, with each result received,
+// including the ones of the losers that return while First waits for them.
+func first(ctx context.Context, retryInterval time.Duration, workers map[string]Worker, maxGs int, o *options, seen func(NamedResult)) NamedResult {
+	defer o.finished(o.now())
+	start := o.now()
+
+	if maxGs > 0 && maxGs < len(workers) {
+		o.turns = make(chan struct{}, maxGs)
+		maxGs = MaxGoroutines
+	}
+
+	ctx, cancel := withCancel(ctx)
+	if o.keepLosers {
+		cancel = func(Reason) {}
+	}
+	defer cancel(ReasonWonByOther)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	var fast, stopped *NamedResult
+	var floor <-chan time.Time
+	errs := newErrorSet(o.maxErrors)
+	results := dispatch(ctx, done, retryInterval, workers, maxGs, o)
+loop:
+	for {
+		var result NamedResult
+		select {
+		case r, ok := <-results:
+			if !ok {
+				break loop
+			}
+			result = r
+		case <-floor:
+			cancel(ReasonWonByOther)
+			drain(results, o.grace, seen)
+			return *fast
+		}
+		if seen != nil {
+			seen(result)
+		}
+		if result.Result.Err != nil {
+			errs.add(result.Name, result.Result.Err)
+			continue
+		}
+		if result.Result.Stopped {
+			if stopped == nil {
+				placeholder := result
+				stopped = &placeholder
+			}
+			continue
+		}
+		if elapsed := o.now().Sub(start); elapsed < o.minLatency {
+			if fast == nil {
+				t := time.NewTimer(o.minLatency - elapsed)
+				defer t.Stop()
+				floor = t.C
+			}
+			slowest := result
+			fast = &slowest
+			continue
+		}
+		cancel(ReasonWonByOther)
+		drain(results, o.grace, seen)
+		return result
+	}
+
+	if fast != nil {
+		return *fast
+	}
+	if stopped != nil {
+		return *stopped
+	}
+
+	errWork := errors.New("all worker functions failed")
+	return NamedResult{Result: Result{Err: &Error{errWork: errs.wrap(errWork), since: o.now().Sub(start), errs: errs.errs, reason: reasonOf(ctx)}}}
+}
+
+// FirstN calls all the worker functions every retry interval until k of the
+// worker functions succeed or the context times out. Once k worker functions
+// succeed, the rest are cancelled and the successful results are returned.
+// If fewer than k succeed, the successful results are returned along with
+// an error holding the last error of each failed worker function. Results
+// stopped by StopWith count as failed. Failed worker functions are not retried anymore, so once too many failed for k
+// to succeed, the rest are cancelled with the ReasonFailFast reason and the
+// error is returned right away. maxGs represents the number of goroutines
+// to run simultaneously to execute all the worker functions.
+func FirstN(ctx context.Context, retryInterval time.Duration, workers map[string]Worker, maxGs int, k int, opts ...Option) (map[string]Result, error) {
+	o := newOptions(opts)
+	defer o.finished(o.now())
 	start := time.Now()
 
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
+	ctx, cancel := withCancel(ctx)
+	defer cancel(ReasonWonByOther)
 
-	switch {
-	case maxGs <= 0 || maxGs >= len(workers):
-		for result := range workMap(ctx, retryInterval, workers) {
+	results := make(map[string]Result)
+	if k <= 0 {
+		return results, nil
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	errs := newErrorSet(o.maxErrors)
+	var failed int
+	ch := dispatch(ctx, done, retryInterval, workers, maxGs, o)
+	for result := range ch {
+		if !result.Result.Success() {
 			if result.Result.Err != nil {
-				continue
+				errs.add(result.Name, result.Result.Err)
+			}
+			failed++
+			if len(workers)-failed < k && ctx.Err() == nil {
+				cancel(ReasonFailFast)
+				drain(ch, o.grace, nil)
+				break
 			}
-			return result.Result
+			continue
 		}
-	default:
-		for result := range workPool(ctx, retryInterval, workers, maxGs) {
-			if result.Result.Err != nil {
-				continue
+		results[result.Name] = result.Result
+		if len(results) == k {
+			cancel(ReasonWonByOther)
+			drain(ch, o.grace, nil)
+			return results, nil
+		}
+	}
+
+	errWork := fmt.Errorf("%d of %d worker functions succeeded", len(results), k)
+	return results, &Error{errWork: errs.wrap(errWork), since: time.Since(start), errs: errs.errs, reason: reasonOf(ctx)}
+}
+
+// drain waits up to d for the cancelled worker functions to return, calling
+// seen, if not nil, with their results. The results already signaled are
+// seen even if d is zero.
+func drain(results <-chan NamedResult, d time.Duration, seen func(NamedResult)) {
+	receive := func(result NamedResult) {
+		if seen != nil {
+			seen(result)
+		}
+	}
+
+	if seen != nil {
+	buffered:
+		for {
+			select {
+			case result, ok := <-results:
+				if !ok {
+					return
+				}
+				receive(result)
+			default:
+				break buffered
+			}
+		}
+	}
+
+	if d <= 0 {
+		return
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	for {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				return
 			}
-			return result.Result
+			receive(result)
+		case <-t.C:
+			return
 		}
 	}
+}
 
-	return Result{Err: &Error{errWork: errors.New("all worker functions failed"), since: time.Since(start)}}
+// errorSet collects the last error of each failed worker function. Once it
+// holds max errors, a zero max meaning no limit, the rest are only counted.
+type errorSet struct {
+	max  int
+	errs map[string]error
+	more int
+}
+
+// newErrorSet returns an empty set keeping at most max errors.
+func newErrorSet(max int) *errorSet {
+	return &errorSet{max: max, errs: make(map[string]error)}
+}
+
+// add keeps the last error of the named worker function if there is room.
+func (s *errorSet) add(name string, err error) {
+	if s.max > 0 && len(s.errs) >= s.max {
+		s.more++
+		return
+	}
+	s.errs[name] = lastError(err)
+}
+
+// wrap adds to errWork how many errors were not kept, if any.
+func (s *errorSet) wrap(errWork error) error {
+	if s.more == 0 {
+		return errWork
+	}
+	return fmt.Errorf("%w (%d errors kept, and %d more)", errWork, len(s.errs), s.more)
 }
 
-// namedResult provides support to match a result to a goroutine that
+// lastError returns the worker function error wrapped by err, or err
+// itself when the worker function never ran.
+func lastError(err error) error {
+	if errWork := errors.Unwrap(err); errWork != nil {
+		return errWork
+	}
+	return err
+}
+
+// NamedResult matches a result to the name of the worker function that
 // performed the work.
-type namedResult struct {
-	name string
+type NamedResult struct {
+	Name string
 	Result
 }
 
+// dispatch calls the map of worker functions using one goroutine per worker
+// function, or a pool of maxGs goroutines when there are more worker
+// functions than that. The consumer closes done when it stops reading the
+// results, so the goroutines do not block forever. A nil done means the
+// consumer reads all the results. A dedicated channel is used instead of
+// ctx.Done() because the context may end while the consumer is still
+// reading, as All does to collect the timeout errors.
+func dispatch(ctx context.Context, done <-chan struct{}, retryInterval time.Duration, workers map[string]Worker, maxGs int, o *options) <-chan NamedResult {
+	if maxGs <= 0 || maxGs >= len(workers) {
+		return workMap(ctx, done, retryInterval, workers, o)
+	}
+	return workPool(ctx, done, retryInterval, workers, maxGs, o)
+}
+
+// send signals the result over the channel unless the consumer is done.
+func send(done <-chan struct{}, results chan<- NamedResult, result NamedResult) {
+	select {
+	case results <- result:
+	case <-done:
+	}
+}
+
 // workMap calls the map of worker functions every retry interval until the
 // worker function succeeds or the context times out. As worker functions
 // complete, their results are signaled over the channel for processing.
-func workMap(ctx context.Context, retryInterval time.Duration, workers map[string]Worker) <-chan namedResult {
+func workMap(ctx context.Context, done <-chan struct{}, retryInterval time.Duration, workers map[string]Worker, o *options) <-chan NamedResult {
 	g := len(workers)
-	results := make(chan namedResult, g)
+	results := make(chan NamedResult, g)
 
 	go func() {
 		var wg sync.WaitGroup
@@ -148,8 +722,8 @@ func workMap(ctx context.Context, retryInterval time.Duration, workers map[strin
 			name, worker := name, worker
 			go func() {
 				defer wg.Done()
-				result := Func(ctx, retryInterval, worker)
-				results <- namedResult{name: name, Result: result}
+				result := work(ctx, name, retryInterval, worker, o)
+				send(done, results, NamedResult{Name: name, Result: result})
 			}()
 		}
 		wg.Wait()
@@ -164,9 +738,9 @@ func workMap(ctx context.Context, retryInterval time.Duration, workers map[strin
 // complete, their results are signaled over the channel for processing. Instead
 // of running each worker in a separate goroutine, the worker functions are
 // executed from a pool of goroutines.
-func workPool(ctx context.Context, retryInterval time.Duration, workers map[string]Worker, concurrency int) <-chan namedResult {
+func workPool(ctx context.Context, done <-chan struct{}, retryInterval time.Duration, workers map[string]Worker, concurrency int, o *options) <-chan NamedResult {
 	g := concurrency
-	results := make(chan namedResult, g)
+	results := make(chan NamedResult, g)
 
 	var wg sync.WaitGroup
 	wg.Add(g)
@@ -181,15 +755,20 @@ func workPool(ctx context.Context, retryInterval time.Duration, workers map[stri
 		go func() {
 			defer wg.Done()
 			for nw := range input {
-				result := Func(ctx, retryInterval, nw.worker)
-				results <- namedResult{name: nw.name, Result: result}
+				result := work(ctx, nw.name, retryInterval, nw.worker, o)
+				send(done, results, NamedResult{Name: nw.name, Result: result})
 			}
 		}()
 	}
 
 	go func() {
+	feed:
 		for name, worker := range workers {
-			input <- namedWorker{name, worker}
+			select {
+			case input <- namedWorker{name, worker}:
+			case <-done:
+				break feed
+			}
 		}
 		close(input)
 		wg.Wait()
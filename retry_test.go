@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"runtime"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -74,6 +75,131 @@ func TestFunc(t *testing.T) {
 			assert.Equal(t, err, errors.Unwrap(result.Err))
 		}
 	})
+
+	t.Run("attempt", func(t *testing.T) {
+		t.Log("Func should pass the attempt number to the worker function through the context.")
+		var attempts []int
+		worker := func(ctx context.Context) (interface{}, error) {
+			attempts = append(attempts, retry.AttemptFromContext(ctx))
+			if len(attempts) < 3 {
+				return nil, errors.New("not yet")
+			}
+			return nil, nil
+		}
+		result := retry.Func(context.Background(), time.Nanosecond, worker)
+		assert.NoError(t, result.Err)
+		assert.Equal(t, []int{1, 2, 3}, attempts)
+		assert.Equal(t, 0, retry.AttemptFromContext(context.Background()))
+	})
+
+	t.Run("lasterror", func(t *testing.T) {
+		t.Log("Func should pass the previous error to the worker function through the context.")
+		errAuth := errors.New("unauthorized")
+		var lastErrs []error
+		worker := func(ctx context.Context) (interface{}, error) {
+			lastErr := retry.LastErrorFromContext(ctx)
+			lastErrs = append(lastErrs, lastErr)
+			if errors.Is(lastErr, errAuth) {
+				return "fallback endpoint", nil
+			}
+			return nil, errAuth
+		}
+		result := retry.Func(context.Background(), time.Nanosecond, worker)
+		assert.NoError(t, result.Err)
+		assert.Equal(t, "fallback endpoint", result.Value)
+		assert.Equal(t, []error{nil, errAuth}, lastErrs)
+		assert.NoError(t, retry.LastErrorFromContext(context.Background()))
+	})
+}
+
+func TestFuncDeadline(t *testing.T) {
+	t.Run("timeout", func(t *testing.T) {
+		t.Log("FuncDeadline should return error mentioning the deadline even if the context lives longer.")
+		errWork := errors.New("foo")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return nil, errWork
+		}
+		deadline := time.Now().Add(2 * time.Millisecond)
+		result := retry.FuncDeadline(context.Background(), time.Millisecond, deadline, worker)
+		if assert.Error(t, result.Err) {
+			assert.IsType(t, &retry.Error{}, result.Err)
+			assert.Equal(t, errWork, errors.Unwrap(result.Err))
+			assert.Regexp(t, "deadline .+ exceeded after .+ : foo", result.Err.Error())
+		}
+	})
+
+	t.Run("failfast", func(t *testing.T) {
+		t.Log("FuncDeadline should return error without calling the worker function if the deadline is in the past.")
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			return nil, nil
+		}
+		result := retry.FuncDeadline(context.Background(), time.Millisecond, time.Now().Add(-time.Second), worker)
+		if assert.Error(t, result.Err) {
+			assert.IsType(t, &retry.Error{}, result.Err)
+			assert.Equal(t, nil, errors.Unwrap(result.Err))
+			assert.Regexp(t, "deadline .+ exceeded after .+", result.Err.Error())
+		}
+		assert.Equal(t, 0, calls)
+	})
+}
+
+func TestTry(t *testing.T) {
+	t.Run("once", func(t *testing.T) {
+		t.Log("Try should call the failing worker function once and wrap its error.")
+		var calls int
+		errWork := errors.New("foo")
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			return nil, errWork
+		}
+		result := retry.Try(context.Background(), worker)
+		assert.Equal(t, 1, calls)
+		var err *retry.Error
+		if assert.True(t, errors.As(result.Err, &err)) {
+			assert.Equal(t, retry.ReasonMaxAttempts, err.Reason())
+			assert.Equal(t, errWork, errors.Unwrap(err))
+		}
+	})
+
+	t.Run("noerror", func(t *testing.T) {
+		t.Log("Try should return the value of the successful worker function.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return "ok", nil
+		}
+		result := retry.Try(context.Background(), worker)
+		assert.NoError(t, result.Err)
+		assert.Equal(t, "ok", result.Value)
+	})
+}
+
+func TestFuncValue(t *testing.T) {
+	t.Run("noerror", func(t *testing.T) {
+		t.Log("FuncValue should return the worker function value.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return "ok", nil
+		}
+		value, err := retry.FuncValue(context.Background(), time.Millisecond, worker)
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", value)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Log("FuncValue should return error because the context timeout exceeded.")
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Millisecond)
+		defer cancel()
+		errWork := errors.New("foo")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return nil, errWork
+		}
+		value, err := retry.FuncValue(ctx, time.Millisecond, worker)
+		if assert.Error(t, err) {
+			assert.IsType(t, &retry.Error{}, err)
+			assert.Equal(t, errWork, errors.Unwrap(err))
+		}
+		assert.Nil(t, value)
+	})
 }
 
 func TestAll(t *testing.T) {
@@ -141,7 +267,101 @@ func TestAll(t *testing.T) {
 	})
 }
 
+func TestAllTimestamps(t *testing.T) {
+	t.Run("retries", func(t *testing.T) {
+		t.Log("All should return when each worker function started and finished, read from the clock.")
+		var calls int32
+		retrier := func(ctx context.Context) (interface{}, error) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				return nil, errors.New("not yet")
+			}
+			return nil, nil
+		}
+		permanent := func(ctx context.Context) (interface{}, error) {
+			return nil, retry.Permanent(errors.New("bad request"))
+		}
+		workers := map[string]retry.Worker{"retrier": retrier, "permanent": permanent}
+		clock := fakeClock{now: time.Now(), step: time.Millisecond}
+		results := retry.All(context.Background(), time.Nanosecond, workers, 1, retry.UseClock(clock.Now))
+		for name, result := range results {
+			assert.False(t, result.StartedAt.IsZero(), name)
+			assert.True(t, result.FinishedAt.After(result.StartedAt), name)
+		}
+		assert.NoError(t, results["retrier"].Err)
+		assert.Error(t, results["permanent"].Err)
+	})
+}
+
+func TestAllErrorName(t *testing.T) {
+	t.Run("name", func(t *testing.T) {
+		t.Log("All should include the worker function name in each error message.")
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+		errWork := errors.New("foo")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return nil, errWork
+		}
+		workers := map[string]retry.Worker{"poll1": worker, "poll2": worker}
+		results := retry.All(ctx, time.Millisecond, workers, retry.MaxGoroutines)
+		for name, result := range results {
+			assert.Regexp(t, `^worker "`+name+`" context cancelled after .+ : foo$`, result.Err.Error())
+			assert.Equal(t, errWork, errors.Unwrap(result.Err))
+		}
+	})
+}
+
+func TestAllOnce(t *testing.T) {
+	t.Run("once", func(t *testing.T) {
+		t.Log("AllOnce should call each worker function exactly once, even when it fails.")
+		var calls [3]int32
+		errWork := errors.New("foo")
+		worker := func(i int, err error) retry.Worker {
+			return func(ctx context.Context) (interface{}, error) {
+				atomic.AddInt32(&calls[i], 1)
+				return i, err
+			}
+		}
+		workers := map[string]retry.Worker{
+			"ok":       worker(0, nil),
+			"fail":     worker(1, errWork),
+			"progress": worker(2, retry.ErrProgress),
+		}
+		results := retry.AllOnce(context.Background(), workers, retry.MaxGoroutines)
+		assert.Len(t, results, 3)
+		assert.Equal(t, [3]int32{1, 1, 1}, calls)
+		assert.NoError(t, results["ok"].Err)
+		for _, name := range []string{"fail", "progress"} {
+			var err *retry.Error
+			if assert.True(t, errors.As(results[name].Err, &err)) {
+				assert.Equal(t, retry.ReasonMaxAttempts, err.Reason())
+			}
+		}
+		assert.True(t, errors.Is(results["fail"].Err, errWork))
+	})
+}
+
 func TestFirst(t *testing.T) {
+	t.Run("fair", func(t *testing.T) {
+		t.Log("First should give every worker function a turn under maxGs.")
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		failing := func(ctx context.Context) (interface{}, error) {
+			time.Sleep(time.Millisecond)
+			return nil, errors.New("slow failure")
+		}
+		var calls int32
+		succeeding := func(ctx context.Context) (interface{}, error) {
+			atomic.AddInt32(&calls, 1)
+			return "success", nil
+		}
+		workers := map[string]retry.Worker{"failing1": failing, "failing2": failing, "failing3": failing, "succeeding": succeeding}
+		result := retry.First(ctx, time.Millisecond, workers, 1)
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, "success", result.Value)
+		}
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
 	t.Run("noerror", func(t *testing.T) {
 		t.Log("First should return the result we chose from three worker functions.")
 		worker5 := func(ctx context.Context) (interface{}, error) {
@@ -228,6 +448,105 @@ func TestFirst(t *testing.T) {
 			assert.Regexp(t, "context cancelled after .+", result.Err.Error())
 		}
 	})
+
+	t.Run("noleak", func(t *testing.T) {
+		t.Log("First should not leak goroutines when it returns before reading all the results.")
+		before := runtime.NumGoroutine()
+		worker := func(ctx context.Context) (interface{}, error) {
+			return "ok", nil
+		}
+		workers := make(map[string]retry.Worker)
+		for i := 0; i < 20; i++ {
+			workers[fmt.Sprint("worker", i)] = worker
+		}
+		for _, maxGs := range []int{retry.MaxGoroutines, 2} {
+			result := retry.First(context.Background(), time.Millisecond, workers, maxGs)
+			assert.NoError(t, result.Err)
+		}
+		for i := 0; i < 1000 && runtime.NumGoroutine() > before; i++ {
+			time.Sleep(time.Millisecond)
+		}
+		assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		t.Log("First should return the last error of each worker function when cancelled externally.")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		err1 := errors.New("worker1 error")
+		err2 := errors.New("worker2 error")
+		var counter int32
+		worker1 := func(ctx context.Context) (interface{}, error) {
+			if atomic.AddInt32(&counter, 1) > 10 {
+				cancel()
+			}
+			return nil, err1
+		}
+		worker2 := func(ctx context.Context) (interface{}, error) {
+			return nil, err2
+		}
+		workers := map[string]retry.Worker{"worker1": worker1, "worker2": worker2}
+		result := retry.First(ctx, time.Millisecond, workers, retry.MaxGoroutines)
+		if assert.Error(t, result.Err) {
+			var err *retry.Error
+			if assert.True(t, errors.As(result.Err, &err)) {
+				assert.Equal(t, map[string]error{"worker1": err1, "worker2": err2}, err.Errors())
+			}
+		}
+	})
+}
+
+func TestFirstN(t *testing.T) {
+	t.Run("noerror", func(t *testing.T) {
+		t.Log("FirstN should return the two fastest successful worker functions.")
+		sleeper := func(d time.Duration) retry.Worker {
+			return func(ctx context.Context) (interface{}, error) {
+				select {
+				case <-time.After(d):
+					return d, nil
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+			}
+		}
+		workers := map[string]retry.Worker{
+			"worker1":  sleeper(time.Millisecond),
+			"worker5":  sleeper(5 * time.Millisecond),
+			"worker50": sleeper(50 * time.Millisecond),
+			"worker60": sleeper(60 * time.Millisecond),
+			"worker70": sleeper(70 * time.Millisecond),
+		}
+		results, err := retry.FirstN(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, 2)
+		assert.NoError(t, err)
+		if assert.Len(t, results, 2) {
+			assert.Equal(t, time.Millisecond, results["worker1"].Value)
+			assert.Equal(t, 5*time.Millisecond, results["worker5"].Value)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Log("FirstN should return the successful results and an error when fewer than k succeed.")
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+		errWork := errors.New("error message")
+		worker1 := func(ctx context.Context) (interface{}, error) {
+			return "ok", nil
+		}
+		worker2 := func(ctx context.Context) (interface{}, error) {
+			return nil, errWork
+		}
+		workers := map[string]retry.Worker{"worker1": worker1, "worker2": worker2}
+		results, err := retry.FirstN(ctx, time.Millisecond, workers, retry.MaxGoroutines, 2)
+		assert.Len(t, results, 1)
+		assert.NoError(t, results["worker1"].Err)
+		if assert.Error(t, err) {
+			assert.Regexp(t, "1 of 2 worker functions succeeded", err.Error())
+			var errRetry *retry.Error
+			if assert.True(t, errors.As(err, &errRetry)) {
+				assert.Equal(t, map[string]error{"worker2": errWork}, errRetry.Errors())
+			}
+		}
+	})
 }
 
 func TestAllWithPooling(t *testing.T) {
@@ -0,0 +1,35 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// Retryer holds the configuration shared by many retry calls, so it does not
+// need to be specified at every call site. The zero value retries without
+// waiting between attempts and runs one goroutine per worker function.
+type Retryer struct {
+	// Interval is the time to wait between attempts.
+	Interval time.Duration
+
+	// MaxGs is the number of goroutines used by DoAll and DoFirst, see All.
+	MaxGs int
+
+	// Options are applied to every call.
+	Options []Option
+}
+
+// Do calls Func with the retryer configuration.
+func (r *Retryer) Do(ctx context.Context, worker Worker) Result {
+	return Func(ctx, r.Interval, worker, r.Options...)
+}
+
+// DoAll calls All with the retryer configuration.
+func (r *Retryer) DoAll(ctx context.Context, workers map[string]Worker) map[string]Result {
+	return All(ctx, r.Interval, workers, r.MaxGs, r.Options...)
+}
+
+// DoFirst calls First with the retryer configuration.
+func (r *Retryer) DoFirst(ctx context.Context, workers map[string]Worker) Result {
+	return First(ctx, r.Interval, workers, r.MaxGs, r.Options...)
+}
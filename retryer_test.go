@@ -0,0 +1,74 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryer(t *testing.T) {
+	var timeouts int32
+	r := retry.Retryer{
+		Interval: time.Millisecond,
+		MaxGs:    retry.MaxGoroutines,
+		Options: []retry.Option{retry.OnTimeout(func(error) {
+			atomic.AddInt32(&timeouts, 1)
+		})},
+	}
+	ok := func(ctx context.Context) (interface{}, error) {
+		return "ok", nil
+	}
+	fail := func(ctx context.Context) (interface{}, error) {
+		return nil, errors.New("fail")
+	}
+
+	t.Run("do", func(t *testing.T) {
+		t.Log("Do should retry the worker function with the retryer configuration.")
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			if calls < 3 {
+				return nil, errors.New("not yet")
+			}
+			return calls, nil
+		}
+		result := r.Do(context.Background(), worker)
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, 3, result.Value)
+		}
+	})
+
+	t.Run("doall", func(t *testing.T) {
+		t.Log("DoAll should return all results and apply the retryer options.")
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+		atomic.StoreInt32(&timeouts, 0)
+		workers := map[string]retry.Worker{"ok": ok, "fail": fail}
+		results := r.DoAll(ctx, workers)
+		assert.Len(t, results, 2)
+		assert.NoError(t, results["ok"].Err)
+		assert.Error(t, results["fail"].Err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&timeouts))
+	})
+
+	t.Run("dofirst", func(t *testing.T) {
+		t.Log("DoFirst should return the first successful result.")
+		var calls int32
+		eventually := func(ctx context.Context) (interface{}, error) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				return nil, errors.New("not yet")
+			}
+			return "ok", nil
+		}
+		workers := map[string]retry.Worker{"ok": ok, "eventually": eventually}
+		result := r.DoFirst(context.Background(), workers)
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, "ok", result.Value)
+		}
+	})
+}
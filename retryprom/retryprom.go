@@ -0,0 +1,63 @@
+// Package retryprom provides a retry.Observer exporting the worker function
+// attempts as Prometheus metrics.
+package retryprom
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Observer implements retry.Observer, counting the attempts, successes and
+// failures of each worker function and measuring how long the attempts
+// take, labeled by worker function name.
+type Observer struct {
+	attempts  *prometheus.CounterVec
+	successes *prometheus.CounterVec
+	failures  *prometheus.CounterVec
+	durations *prometheus.HistogramVec
+}
+
+// New returns an Observer with its metrics registered in reg. The metrics
+// are retry_attempts_total, retry_successes_total, retry_failures_total and
+// retry_attempt_duration_seconds, all with the worker label.
+func New(reg prometheus.Registerer) (*Observer, error) {
+	labels := []string{"worker"}
+	obs := Observer{
+		attempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "retry_attempts_total",
+			Help: "Number of worker function calls.",
+		}, labels),
+		successes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "retry_successes_total",
+			Help: "Number of worker function calls that succeeded.",
+		}, labels),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "retry_failures_total",
+			Help: "Number of worker function calls that failed.",
+		}, labels),
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "retry_attempt_duration_seconds",
+			Help:    "How long the worker function calls took.",
+			Buckets: prometheus.DefBuckets,
+		}, labels),
+	}
+
+	for _, c := range []prometheus.Collector{obs.attempts, obs.successes, obs.failures, obs.durations} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return &obs, nil
+}
+
+// AttemptCompleted implements the retry.Observer interface.
+func (obs *Observer) AttemptCompleted(name string, attempt int, d time.Duration, err error) {
+	obs.attempts.WithLabelValues(name).Inc()
+	if err != nil {
+		obs.failures.WithLabelValues(name).Inc()
+	} else {
+		obs.successes.WithLabelValues(name).Inc()
+	}
+	obs.durations.WithLabelValues(name).Observe(d.Seconds())
+}
@@ -0,0 +1,84 @@
+package retryprom_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/massahud/retry/retryprom"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserver(t *testing.T) {
+	t.Run("metrics", func(t *testing.T) {
+		t.Log("The observer should count the attempts of each worker function.")
+		reg := prometheus.NewRegistry()
+		obs, err := retryprom.New(reg)
+		if !assert.NoError(t, err) {
+			return
+		}
+		var calls int
+		flaky := func(ctx context.Context) (interface{}, error) {
+			calls++
+			if calls < 3 {
+				return nil, errors.New("not yet")
+			}
+			return "ok", nil
+		}
+		ok := func(ctx context.Context) (interface{}, error) {
+			return "ok", nil
+		}
+		workers := map[string]retry.Worker{"flaky": flaky, "ok": ok}
+		retry.All(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, retry.UseObserver(obs))
+
+		assert.Equal(t, 3.0, counter(t, reg, "retry_attempts_total", "flaky"))
+		assert.Equal(t, 1.0, counter(t, reg, "retry_successes_total", "flaky"))
+		assert.Equal(t, 2.0, counter(t, reg, "retry_failures_total", "flaky"))
+		assert.Equal(t, 1.0, counter(t, reg, "retry_attempts_total", "ok"))
+		assert.Equal(t, 0.0, counter(t, reg, "retry_failures_total", "ok"))
+
+		families, err := reg.Gather()
+		assert.NoError(t, err)
+		var samples uint64
+		for _, family := range families {
+			if family.GetName() == "retry_attempt_duration_seconds" {
+				for _, m := range family.GetMetric() {
+					samples += m.GetHistogram().GetSampleCount()
+				}
+			}
+		}
+		assert.Equal(t, uint64(4), samples)
+	})
+
+	t.Run("registered", func(t *testing.T) {
+		t.Log("New should fail when the metrics are already registered.")
+		reg := prometheus.NewRegistry()
+		_, err := retryprom.New(reg)
+		assert.NoError(t, err)
+		_, err = retryprom.New(reg)
+		assert.Error(t, err)
+	})
+}
+
+// counter returns the value of the counter for the worker function, zero if
+// it was never incremented.
+func counter(t *testing.T, reg *prometheus.Registry, name string, worker string) float64 {
+	families, err := reg.Gather()
+	assert.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			for _, label := range m.GetLabel() {
+				if label.GetName() == "worker" && label.GetValue() == worker {
+					return m.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,33 @@
+// Package retrytest provides worker functions with a fixed behavior, for
+// testing code that uses the retry package. The worker functions are safe
+// to call from several goroutines, as All does.
+package retrytest
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/massahud/retry"
+)
+
+// FailThenSucceed returns a worker function that fails the first n calls and
+// then succeeds returning the value. The calls are counted across all the
+// goroutines calling the worker function.
+func FailThenSucceed(n int, value interface{}) retry.Worker {
+	var calls int64
+	return func(ctx context.Context) (interface{}, error) {
+		call := atomic.AddInt64(&calls, 1)
+		if call <= int64(n) {
+			return nil, fmt.Errorf("call %d of %d failed", call, n)
+		}
+		return value, nil
+	}
+}
+
+// AlwaysFail returns a worker function that always fails with err.
+func AlwaysFail(err error) retry.Worker {
+	return func(ctx context.Context) (interface{}, error) {
+		return nil, err
+	}
+}
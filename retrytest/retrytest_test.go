@@ -0,0 +1,66 @@
+package retrytest_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/massahud/retry/retrytest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailThenSucceed(t *testing.T) {
+	t.Run("func", func(t *testing.T) {
+		t.Log("FailThenSucceed should fail n times and then succeed.")
+		worker := retrytest.FailThenSucceed(2, "done")
+		for i := 1; i <= 2; i++ {
+			_, err := worker(context.Background())
+			assert.Error(t, err)
+		}
+		value, err := worker(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "done", value)
+
+		result := retry.Func(context.Background(), time.Millisecond, retrytest.FailThenSucceed(3, "done"))
+		assert.NoError(t, result.Err)
+		assert.Equal(t, "done", result.Value)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		t.Log("FailThenSucceed should count the calls from all goroutines.")
+		worker := retrytest.FailThenSucceed(50, "done")
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		var failures int
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := worker(context.Background()); err != nil {
+					mu.Lock()
+					failures++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, 50, failures)
+	})
+}
+
+func TestAlwaysFail(t *testing.T) {
+	t.Run("all", func(t *testing.T) {
+		t.Log("AlwaysFail should fail every call until the context times out.")
+		errDown := errors.New("down")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		workers := map[string]retry.Worker{"down1": retrytest.AlwaysFail(errDown), "down2": retrytest.AlwaysFail(errDown)}
+		results := retry.All(ctx, time.Millisecond, workers, retry.MaxGoroutines)
+		for _, name := range []string{"down1", "down2"} {
+			assert.True(t, errors.Is(results[name].Err, errDown))
+		}
+	})
+}
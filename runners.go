@@ -0,0 +1,28 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// FirstWithRunnersUp is like First, but also returns the results of all the
+// worker functions, keyed by name, including the winner. The runners-up that
+// completed by the time FirstWithRunnersUp returned, or during the grace
+// period set by LoserGrace, have their own result. The ones still running
+// have an error with the ReasonWonByOther reason.
+func FirstWithRunnersUp(ctx context.Context, retryInterval time.Duration, workers map[string]Worker, maxGs int, opts ...Option) (Result, map[string]Result) {
+	start := time.Now()
+	results := make(map[string]Result, len(workers))
+
+	winner := first(ctx, retryInterval, workers, maxGs, newOptions(opts), func(result NamedResult) {
+		results[result.Name] = result.Result
+	})
+
+	for name := range workers {
+		if _, ok := results[name]; !ok {
+			results[name] = Result{Err: &Error{name: name, since: time.Since(start), reason: ReasonWonByOther}}
+		}
+	}
+
+	return winner.Result, results
+}
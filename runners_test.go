@@ -0,0 +1,64 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFirstWithRunnersUp(t *testing.T) {
+	t.Run("runnersup", func(t *testing.T) {
+		t.Log("FirstWithRunnersUp should include the runners-up that finished in time.")
+		started := make(chan struct{})
+		winner := func(ctx context.Context) (interface{}, error) {
+			<-started
+			time.Sleep(10 * time.Millisecond)
+			return "winner", nil
+		}
+		runnerUp := func(ctx context.Context) (interface{}, error) {
+			<-started
+			time.Sleep(10 * time.Millisecond)
+			return "runner-up", nil
+		}
+		slow := func(ctx context.Context) (interface{}, error) {
+			close(started)
+			<-ctx.Done()
+			time.Sleep(time.Second)
+			return nil, ctx.Err()
+		}
+		workers := map[string]retry.Worker{"winner": winner, "runnerup": runnerUp, "slow": slow}
+		result, results := retry.FirstWithRunnersUp(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, retry.LoserGrace(100*time.Millisecond))
+		assert.NoError(t, result.Err)
+		assert.Len(t, results, 3)
+		assert.Equal(t, map[string]interface{}{"winner": "winner", "runnerup": "runner-up"}, map[string]interface{}{
+			"winner":   results["winner"].Value,
+			"runnerup": results["runnerup"].Value,
+		})
+		var err *retry.Error
+		if assert.True(t, errors.As(results["slow"].Err, &err)) {
+			assert.Equal(t, retry.ReasonWonByOther, err.Reason())
+			assert.Regexp(t, `^worker "slow" cancelled by another worker function after`, err.Error())
+		}
+	})
+
+	t.Run("allfail", func(t *testing.T) {
+		t.Log("FirstWithRunnersUp should return the error of each failed worker function.")
+		fail := func(ctx context.Context) (interface{}, error) {
+			return nil, retry.Permanent(errors.New("down"))
+		}
+		workers := map[string]retry.Worker{"a": fail, "b": fail}
+		result, results := retry.FirstWithRunnersUp(context.Background(), time.Millisecond, workers, retry.MaxGoroutines)
+		assert.Error(t, result.Err)
+		assert.Len(t, results, 2)
+		for _, r := range results {
+			var err *retry.Error
+			if assert.True(t, errors.As(r.Err, &err)) {
+				assert.Equal(t, retry.ReasonPermanent, err.Reason())
+			}
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package retry
+
+import (
+	"sync"
+	"time"
+)
+
+// Scheduler decides when the worker functions are retried, for aligning the
+// retries to wall-clock boundaries or driving them from an event loop.
+type Scheduler interface {
+	// After returns a channel that receives when the retry must happen,
+	// once the wait of d has passed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// realScheduler schedules the retries with the system timers.
+type realScheduler struct{}
+
+// After implements the Scheduler interface.
+func (realScheduler) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// RealScheduler is the Scheduler using the system timers. Without
+// UseScheduler, the system timers are used too.
+var RealScheduler Scheduler = realScheduler{}
+
+// UseScheduler sets the scheduler that decides when the worker functions are
+// retried, after the wait between attempts.
+func UseScheduler(s Scheduler) Option {
+	return func(o *options) {
+		o.scheduler = s
+	}
+}
+
+// SharedTicker makes the worker functions of a call retry on the ticks of a
+// single shared ticker, instead of each waiting on its own timer. Every wait
+// is rounded up to the next tick boundary, so retries happen up to one tick
+// later than the retry interval or backoff asks, in exchange for far fewer
+// runtime timers when there are many worker functions. A tick of zero or
+// less restores the system timers.
+func SharedTicker(tick time.Duration) Option {
+	return func(o *options) {
+		if tick <= 0 {
+			o.scheduler = nil
+			return
+		}
+		o.scheduler = &tickScheduler{tick: tick}
+	}
+}
+
+// tickScheduler implements SharedTicker. The ticker runs only while there
+// are waits pending.
+type tickScheduler struct {
+	tick time.Duration
+
+	mu      sync.Mutex
+	waits   []tickWait
+	running bool
+}
+
+// tickWait is a wait pending on the ticker.
+type tickWait struct {
+	due time.Time
+	c   chan time.Time
+}
+
+// After implements the Scheduler interface.
+func (s *tickScheduler) After(d time.Duration) <-chan time.Time {
+	c := make(chan time.Time, 1)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.waits = append(s.waits, tickWait{due: time.Now().Add(d), c: c})
+	if !s.running {
+		s.running = true
+		go s.run()
+	}
+	return c
+}
+
+// run fires the waits that are due on each tick, until none is left.
+func (s *tickScheduler) run() {
+	t := time.NewTicker(s.tick)
+	defer t.Stop()
+
+	for now := range t.C {
+		s.mu.Lock()
+		pending := s.waits[:0]
+		for _, w := range s.waits {
+			if now.Before(w.due) {
+				pending = append(pending, w)
+				continue
+			}
+			w.c <- now
+		}
+		for i := len(pending); i < len(s.waits); i++ {
+			s.waits[i] = tickWait{}
+		}
+		s.waits = pending
+		if len(pending) == 0 {
+			s.running = false
+			s.mu.Unlock()
+			return
+		}
+		s.mu.Unlock()
+	}
+}
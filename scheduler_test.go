@@ -0,0 +1,115 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+// manualScheduler fires the retries when the test says so.
+type manualScheduler struct {
+	waits chan chan time.Time
+}
+
+func (s *manualScheduler) After(d time.Duration) <-chan time.Time {
+	wait := make(chan time.Time, 1)
+	s.waits <- wait
+	return wait
+}
+
+func TestUseScheduler(t *testing.T) {
+	t.Run("manual", func(t *testing.T) {
+		t.Log("Func should retry only when the scheduler fires.")
+		var calls int32
+		worker := func(ctx context.Context) (interface{}, error) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				return nil, errors.New("not yet")
+			}
+			return "done", nil
+		}
+		s := &manualScheduler{waits: make(chan chan time.Time)}
+		results := make(chan retry.Result)
+		go func() {
+			results <- retry.Func(context.Background(), time.Hour, worker, retry.UseScheduler(s))
+		}()
+		for i := 1; i <= 2; i++ {
+			wait := <-s.waits
+			assert.Equal(t, int32(i), atomic.LoadInt32(&calls))
+			wait <- time.Now()
+		}
+		result := <-results
+		assert.NoError(t, result.Err)
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("real", func(t *testing.T) {
+		t.Log("Func should retry after the wait with the real scheduler.")
+		var calls int32
+		worker := func(ctx context.Context) (interface{}, error) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				return nil, errors.New("not yet")
+			}
+			return "done", nil
+		}
+		result := retry.Func(context.Background(), time.Millisecond, worker, retry.UseScheduler(retry.RealScheduler))
+		assert.NoError(t, result.Err)
+	})
+}
+
+func TestSharedTicker(t *testing.T) {
+	t.Run("tick", func(t *testing.T) {
+		t.Log("All should retry the worker functions on the shared ticker.")
+		worker := func() retry.Worker {
+			var calls int32
+			return func(ctx context.Context) (interface{}, error) {
+				if atomic.AddInt32(&calls, 1) < 3 {
+					return nil, errors.New("not yet")
+				}
+				return "done", nil
+			}
+		}
+		workers := map[string]retry.Worker{"a": worker(), "b": worker(), "c": worker()}
+		start := time.Now()
+		results := retry.All(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, retry.SharedTicker(10*time.Millisecond))
+		assert.Equal(t, 3, retry.CountSuccesses(results))
+		assert.GreaterOrEqual(t, int64(time.Since(start)), int64(20*time.Millisecond))
+	})
+}
+
+// benchRetryWorkers returns n worker functions that fail twice and then
+// succeed.
+func benchRetryWorkers(n int) map[string]retry.Worker {
+	workers := make(map[string]retry.Worker, n)
+	for i := 0; i < n; i++ {
+		var calls int32
+		workers[fmt.Sprint("worker", i)] = func(ctx context.Context) (interface{}, error) {
+			if atomic.AddInt32(&calls, 1)%3 != 0 {
+				return nil, errors.New("not yet")
+			}
+			return nil, nil
+		}
+	}
+	return workers
+}
+
+func BenchmarkTimers(b *testing.B) {
+	workers := benchRetryWorkers(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		retry.All(context.Background(), time.Millisecond, workers, retry.MaxGoroutines)
+	}
+}
+
+func BenchmarkSharedTicker(b *testing.B) {
+	workers := benchRetryWorkers(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		retry.All(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, retry.SharedTicker(time.Millisecond))
+	}
+}
@@ -0,0 +1,44 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// NamedWorker is a worker function with its name, for the calls running
+// worker functions in order.
+type NamedWorker struct {
+	Name   string
+	Worker Worker
+}
+
+// Sequence calls the worker functions one after the other, in order, each
+// every retry interval until it succeeds or the context ends, like Func. It
+// stops at the first worker function that fails, so the results only hold
+// the worker functions that ran. ContinueOnFailure makes it run the next
+// worker functions anyway, until the context ends.
+func Sequence(ctx context.Context, retryInterval time.Duration, steps []NamedWorker, opts ...Option) map[string]Result {
+	o := newOptions(opts)
+	defer o.finished(o.now())
+
+	results := make(map[string]Result, len(steps))
+	for _, step := range steps {
+		if len(results) > 0 && ctx.Err() != nil {
+			break
+		}
+		result := work(ctx, step.Name, retryInterval, step.Worker, o)
+		results[step.Name] = result
+		if result.Err != nil && !o.continueOnFailure {
+			break
+		}
+	}
+	return results
+}
+
+// ContinueOnFailure makes Sequence run the next worker functions after one
+// fails, instead of stopping.
+func ContinueOnFailure() Option {
+	return func(o *options) {
+		o.continueOnFailure = true
+	}
+}
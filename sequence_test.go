@@ -0,0 +1,61 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSequence(t *testing.T) {
+	var order []string
+	step := func(name string, err error) retry.NamedWorker {
+		return retry.NamedWorker{Name: name, Worker: func(ctx context.Context) (interface{}, error) {
+			order = append(order, name)
+			return name, err
+		}}
+	}
+
+	t.Run("stop", func(t *testing.T) {
+		t.Log("Sequence should run the worker functions in order and stop at the first failure.")
+		order = nil
+		errDB := retry.Permanent(errors.New("db down"))
+		steps := []retry.NamedWorker{step("network", nil), step("db", errDB), step("cache", nil)}
+		results := retry.Sequence(context.Background(), time.Millisecond, steps)
+		assert.Equal(t, []string{"network", "db"}, order)
+		assert.Len(t, results, 2)
+		assert.NoError(t, results["network"].Err)
+		assert.Regexp(t, `^worker "db" retry stopped after .* : db down$`, results["db"].Err.Error())
+		assert.NotContains(t, results, "cache")
+	})
+
+	t.Run("continue", func(t *testing.T) {
+		t.Log("Sequence should run all the worker functions with ContinueOnFailure.")
+		order = nil
+		errDB := retry.Permanent(errors.New("db down"))
+		steps := []retry.NamedWorker{step("network", nil), step("db", errDB), step("cache", nil)}
+		results := retry.Sequence(context.Background(), time.Millisecond, steps, retry.ContinueOnFailure())
+		assert.Equal(t, []string{"network", "db", "cache"}, order)
+		assert.Len(t, results, 3)
+		assert.Error(t, results["db"].Err)
+		assert.NoError(t, results["cache"].Err)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Log("Sequence should not run the next worker functions once the context ended.")
+		order = nil
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		steps := []retry.NamedWorker{step("network", errors.New("unreachable")), step("db", nil)}
+		results := retry.Sequence(ctx, time.Millisecond, steps, retry.ContinueOnFailure())
+		assert.Len(t, results, 1)
+		var err *retry.Error
+		if assert.True(t, errors.As(results["network"].Err, &err)) {
+			assert.Equal(t, retry.ReasonTimeout, err.Reason())
+		}
+		assert.NotContains(t, order, "db")
+	})
+}
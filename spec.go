@@ -0,0 +1,59 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WorkerSpec is a worker function with its own retry configuration.
+type WorkerSpec struct {
+	// Worker is the worker function to retry.
+	Worker Worker
+
+	// Interval is the time to wait between attempts.
+	Interval time.Duration
+
+	// Backoff, if not nil, decides the wait between attempts instead of
+	// Interval.
+	Backoff Backoff
+
+	// MaxAttempts, if greater than zero, limits the number of attempts.
+	MaxAttempts int
+}
+
+// AllSpec is like All, but each worker function is retried with the
+// configuration of its spec, running one goroutine per worker function.
+// The options are applied to all specs, before the spec configuration.
+func AllSpec(ctx context.Context, specs map[string]WorkerSpec, opts ...Option) map[string]Result {
+	results := make(map[string]Result)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	wg.Add(len(specs))
+	for name, spec := range specs {
+		name, spec := name, spec
+		go func() {
+			defer wg.Done()
+			result := work(ctx, name, spec.Interval, spec.Worker, spec.options(opts))
+			mu.Lock()
+			defer mu.Unlock()
+			results[name] = result
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// options returns the configuration of the spec over the options.
+func (spec WorkerSpec) options(opts []Option) *options {
+	o := newOptions(opts)
+	if spec.Backoff != nil {
+		o.backoff = spec.Backoff
+	}
+	if spec.MaxAttempts > 0 {
+		o.maxAttempts = spec.MaxAttempts
+	}
+	return o
+}
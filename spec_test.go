@@ -0,0 +1,54 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllSpec(t *testing.T) {
+	t.Run("intervals", func(t *testing.T) {
+		t.Log("AllSpec should poll each worker function at its own interval.")
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Millisecond)
+		defer cancel()
+		var fast, slow int32
+		counter := func(calls *int32) retry.Worker {
+			return func(ctx context.Context) (interface{}, error) {
+				atomic.AddInt32(calls, 1)
+				return nil, errors.New("never")
+			}
+		}
+		specs := map[string]retry.WorkerSpec{
+			"fast": {Worker: counter(&fast), Interval: time.Millisecond},
+			"slow": {Worker: counter(&slow), Interval: 25 * time.Millisecond},
+		}
+		results := retry.AllSpec(ctx, specs)
+		assert.Len(t, results, 2)
+		assert.Error(t, results["fast"].Err)
+		assert.Error(t, results["slow"].Err)
+		assert.Greater(t, atomic.LoadInt32(&fast), int32(10))
+		assert.LessOrEqual(t, atomic.LoadInt32(&slow), int32(3))
+	})
+
+	t.Run("maxattempts", func(t *testing.T) {
+		t.Log("AllSpec should stop each worker function after its max attempts.")
+		var calls int32
+		worker := func(ctx context.Context) (interface{}, error) {
+			atomic.AddInt32(&calls, 1)
+			return nil, errors.New("never")
+		}
+		specs := map[string]retry.WorkerSpec{
+			"limited": {Worker: worker, Interval: time.Nanosecond, MaxAttempts: 3},
+		}
+		results := retry.AllSpec(context.Background(), specs)
+		if assert.Error(t, results["limited"].Err) {
+			assert.Regexp(t, "gave up after 3 attempts", results["limited"].Err.Error())
+		}
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+}
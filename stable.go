@@ -0,0 +1,51 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// stable holds the state of a worker function wrapped by Stable.
+type stable struct {
+	worker Worker
+	k      int
+
+	mu      sync.Mutex
+	streak  int
+	errWork error
+}
+
+// Stable wraps the worker function so it only succeeds after k consecutive
+// successful calls. Any error resets the count. While the streak is in
+// progress an error wrapping the last worker function error, if any, is
+// returned, so a context ending mid-streak still reports why the worker
+// function was not stable.
+func Stable(worker Worker, k int) Worker {
+	s := stable{worker: worker, k: k}
+	return s.work
+}
+
+// work calls the worker function and tracks the streak of successes.
+func (s *stable) work(ctx context.Context) (interface{}, error) {
+	value, err := s.worker(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil {
+		s.streak = 0
+		s.errWork = err
+		return nil, err
+	}
+
+	s.streak++
+	if s.streak >= s.k {
+		return value, nil
+	}
+
+	if s.errWork != nil {
+		return nil, fmt.Errorf("%d of %d consecutive successes : %w", s.streak, s.k, s.errWork)
+	}
+	return nil, fmt.Errorf("%d of %d consecutive successes", s.streak, s.k)
+}
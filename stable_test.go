@@ -0,0 +1,49 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStable(t *testing.T) {
+	t.Run("noerror", func(t *testing.T) {
+		t.Log("Stable should only succeed after 3 consecutive successes.")
+		results := []error{nil, errors.New("flap"), nil, nil, nil, nil}
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			err := results[calls]
+			calls++
+			return calls, err
+		}
+		result := retry.Func(context.Background(), time.Nanosecond, retry.Stable(worker, 3))
+		if assert.NoError(t, result.Err) {
+			assert.Equal(t, 5, result.Value)
+			assert.Equal(t, 5, calls)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Log("Stable should return the last error if the context ends mid-streak.")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		errWork := errors.New("flap")
+		var calls int
+		worker := func(ctx context.Context) (interface{}, error) {
+			calls++
+			if calls == 1 {
+				return nil, errWork
+			}
+			return "ok", nil
+		}
+		result := retry.Func(ctx, time.Millisecond, retry.Stable(worker, 1000))
+		if assert.Error(t, result.Err) {
+			assert.IsType(t, &retry.Error{}, result.Err)
+			assert.True(t, errors.Is(result.Err, errWork))
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// Stats reports how long each worker function of FirstStats took, measured
+// from the start of the call with the clock set by UseClock.
+type Stats struct {
+	// WinnerName is the name of the successful worker function, empty if
+	// all of them failed.
+	WinnerName string
+
+	// WinnerLatency is how long the successful worker function took.
+	WinnerLatency time.Duration
+
+	// Latencies has how long each worker function took to complete, or,
+	// for the ones still running when FirstStats returned, the time
+	// elapsed so far.
+	Latencies map[string]time.Duration
+}
+
+// FirstStats is like First, but also returns the latency of the winner and
+// of the other worker functions, for SLA reporting.
+func FirstStats(ctx context.Context, retryInterval time.Duration, workers map[string]Worker, maxGs int, opts ...Option) (Result, Stats) {
+	o := newOptions(opts)
+	start := o.now()
+	stats := Stats{Latencies: make(map[string]time.Duration, len(workers))}
+
+	winner := first(ctx, retryInterval, workers, maxGs, o, func(result NamedResult) {
+		stats.Latencies[result.Name] = o.now().Sub(start)
+	})
+	if winner.Success() {
+		stats.WinnerName = winner.Name
+		stats.WinnerLatency = stats.Latencies[winner.Name]
+	}
+
+	elapsed := o.now().Sub(start)
+	for name := range workers {
+		if _, ok := stats.Latencies[name]; !ok {
+			stats.Latencies[name] = elapsed
+		}
+	}
+
+	return winner.Result, stats
+}
@@ -0,0 +1,61 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFirstStats(t *testing.T) {
+	t.Run("winner", func(t *testing.T) {
+		t.Log("FirstStats should report the winner with the smallest latency.")
+		fail := func(ctx context.Context) (interface{}, error) {
+			time.Sleep(time.Millisecond)
+			return nil, retry.Permanent(errors.New("down"))
+		}
+		fast := func(ctx context.Context) (interface{}, error) {
+			time.Sleep(10 * time.Millisecond)
+			return "fast", nil
+		}
+		slow := func(ctx context.Context) (interface{}, error) {
+			select {
+			case <-time.After(time.Second):
+				return "slow", nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		workers := map[string]retry.Worker{"fail": fail, "fast": fast, "slow": slow}
+		result, stats := retry.FirstStats(context.Background(), time.Millisecond, workers, retry.MaxGoroutines)
+		assert.NoError(t, result.Err)
+		assert.Equal(t, "fast", stats.WinnerName)
+		assert.Equal(t, stats.Latencies["fast"], stats.WinnerLatency)
+		assert.Len(t, stats.Latencies, 3)
+		assert.LessOrEqual(t, int64(stats.WinnerLatency), int64(stats.Latencies["slow"]))
+		assert.Less(t, int64(stats.Latencies["fail"]), int64(stats.WinnerLatency))
+	})
+
+	t.Run("clock", func(t *testing.T) {
+		t.Log("FirstStats should measure the latencies with the clock.")
+		started := make(chan struct{})
+		winner := func(ctx context.Context) (interface{}, error) {
+			<-started
+			return "winner", nil
+		}
+		loser := func(ctx context.Context) (interface{}, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		clock := &fakeClock{now: time.Unix(0, 0), step: time.Millisecond}
+		workers := map[string]retry.Worker{"winner": winner, "loser": loser}
+		_, stats := retry.FirstStats(context.Background(), time.Millisecond, workers, retry.MaxGoroutines, retry.UseClock(clock.Now))
+		assert.Equal(t, "winner", stats.WinnerName)
+		assert.Greater(t, int64(stats.WinnerLatency), int64(0))
+		assert.Greater(t, int64(stats.Latencies["loser"]), int64(stats.WinnerLatency))
+	})
+}
@@ -0,0 +1,60 @@
+//go:build go1.18
+// +build go1.18
+
+package retry
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// ValueAs returns the result value as a T. It returns false if the value is
+// nil or is not a T.
+func ValueAs[T any](r Result) (T, bool) {
+	value, ok := r.Value.(T)
+	return value, ok
+}
+
+// MustValue returns the result value as a T. It panics if the value is nil
+// or is not a T.
+func MustValue[T any](r Result) T {
+	value, ok := r.Value.(T)
+	if !ok {
+		panic(fmt.Sprintf("retry: result value is %T, not %v", r.Value, reflect.TypeOf((*T)(nil)).Elem()))
+	}
+	return value
+}
+
+// FuncValueG is like FuncValue but returns the value as a T. It returns an
+// error if the worker function succeeds with a value that is not a T.
+func FuncValueG[T any](ctx context.Context, retryInterval time.Duration, worker Worker, opts ...Option) (T, error) {
+	result := Func(ctx, retryInterval, worker, opts...)
+	if result.Err != nil {
+		var zero T
+		return zero, result.Err
+	}
+	value, ok := ValueAs[T](result)
+	if !ok {
+		return value, fmt.Errorf("retry: result value is %T, not %v", result.Value, reflect.TypeOf((*T)(nil)).Elem())
+	}
+	return value, nil
+}
+
+// Reduce folds the results into a single value, calling f for each result
+// in name order, starting from init.
+func Reduce[T any](results map[string]Result, init T, f func(acc T, name string, r Result) T) T {
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	acc := init
+	for _, name := range names {
+		acc = f(acc, name, results[name])
+	}
+	return acc
+}
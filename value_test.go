@@ -0,0 +1,119 @@
+//go:build go1.18
+// +build go1.18
+
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/massahud/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValueAs(t *testing.T) {
+	t.Run("noerror", func(t *testing.T) {
+		t.Log("ValueAs should return the value when it has the expected type.")
+		value, ok := retry.ValueAs[string](retry.Result{Value: "ok"})
+		assert.True(t, ok)
+		assert.Equal(t, "ok", value)
+	})
+
+	t.Run("wrongtype", func(t *testing.T) {
+		t.Log("ValueAs should return false when the value has another type.")
+		value, ok := retry.ValueAs[string](retry.Result{Value: 42})
+		assert.False(t, ok)
+		assert.Equal(t, "", value)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		t.Log("ValueAs should return false when the value is nil.")
+		value, ok := retry.ValueAs[*int](retry.Result{})
+		assert.False(t, ok)
+		assert.Nil(t, value)
+	})
+}
+
+func TestMustValue(t *testing.T) {
+	t.Run("noerror", func(t *testing.T) {
+		t.Log("MustValue should return the value when it has the expected type.")
+		assert.Equal(t, 42, retry.MustValue[int](retry.Result{Value: 42}))
+	})
+
+	t.Run("wrongtype", func(t *testing.T) {
+		t.Log("MustValue should panic with both types when the value has another type.")
+		assert.PanicsWithValue(t, "retry: result value is int, not string", func() {
+			retry.MustValue[string](retry.Result{Value: 42})
+		})
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		t.Log("MustValue should panic when the value is nil.")
+		assert.PanicsWithValue(t, "retry: result value is <nil>, not string", func() {
+			retry.MustValue[string](retry.Result{})
+		})
+	})
+}
+
+func TestFuncValueG(t *testing.T) {
+	t.Run("noerror", func(t *testing.T) {
+		t.Log("FuncValueG should return the typed value.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return 42, nil
+		}
+		value, err := retry.FuncValueG[int](context.Background(), time.Millisecond, worker)
+		assert.NoError(t, err)
+		assert.Equal(t, 42, value)
+	})
+
+	t.Run("wrongtype", func(t *testing.T) {
+		t.Log("FuncValueG should return an error when the value has another type.")
+		worker := func(ctx context.Context) (interface{}, error) {
+			return 42, nil
+		}
+		value, err := retry.FuncValueG[string](context.Background(), time.Millisecond, worker)
+		assert.EqualError(t, err, "retry: result value is int, not string")
+		assert.Equal(t, "", value)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Log("FuncValueG should return the Func error on timeout.")
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Millisecond)
+		defer cancel()
+		worker := func(ctx context.Context) (interface{}, error) {
+			return nil, errors.New("fail")
+		}
+		value, err := retry.FuncValueG[int](ctx, time.Millisecond, worker)
+		assert.IsType(t, &retry.Error{}, err)
+		assert.Equal(t, 0, value)
+	})
+}
+
+func TestReduce(t *testing.T) {
+	t.Run("count", func(t *testing.T) {
+		t.Log("Reduce should fold the results into the count of successes.")
+		results := map[string]retry.Result{
+			"a": {Value: 1},
+			"b": {Err: errors.New("fail")},
+			"c": {Value: 3},
+		}
+		count := retry.Reduce(results, 0, func(acc int, name string, r retry.Result) int {
+			if r.Success() {
+				acc++
+			}
+			return acc
+		})
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("order", func(t *testing.T) {
+		t.Log("Reduce should call the function in name order.")
+		results := map[string]retry.Result{"c": {}, "a": {}, "b": {}}
+		names := retry.Reduce(results, "", func(acc string, name string, r retry.Result) string {
+			return acc + name
+		})
+		assert.Equal(t, "abc", names)
+	})
+}